@@ -0,0 +1,41 @@
+// Команда calendar-link печатает подписанную ссылку на календарь списаний пользователя.
+// В сервисе нет авторизации, поэтому ссылки выдаёт оператор, а не публичный API:
+//
+//	CALENDAR_SECRET=... go run ./cmd/calendar-link -base https://subs.example.com <user_id>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/calendar"
+)
+
+func main() {
+	base := flag.String("base", "http://localhost:8080", "public address of the service")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatalf("usage: calendar-link [-base URL] <user_id>")
+	}
+
+	userID, err := uuid.Parse(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("invalid user_id: %v", err)
+	}
+	secret := os.Getenv("CALENDAR_SECRET")
+	if secret == "" {
+		log.Fatalf("CALENDAR_SECRET is not set")
+	}
+	feed, err := url.Parse(*base)
+	if err != nil {
+		log.Fatalf("invalid base URL: %v", err)
+	}
+
+	feed = feed.JoinPath("users", userID.String(), "calendar.ics")
+	feed.RawQuery = url.Values{"token": {calendar.Token([]byte(secret), userID)}}.Encode()
+	fmt.Println(feed.String())
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"subscriptions/internal/config"
+	"subscriptions/internal/exchange"
 	"subscriptions/internal/handler"
 	"subscriptions/internal/logger"
+	"subscriptions/internal/model"
 	"subscriptions/internal/repository"
 	"subscriptions/internal/usecase"
 	"syscall"
@@ -26,22 +30,65 @@ func main() {
 	logger_ := logger.New()
 	logger_.Info("Starting subscription service...")
 
-	db, err := repository.InitDB(cfg)
-	if err != nil {
-		logger_.Fatalf("failed to initialize database: %v", err)
+	var repo repository.Repository
+	switch cfg.Storage {
+	case config.StorageMemory:
+		repo = repository.NewMemoryRepository()
+		logger_.Warn("Using in-memory storage, data will be lost on exit")
+	default:
+		db, err := repository.InitDB(cfg)
+		if err != nil {
+			logger_.Fatalf("failed to initialize database: %v", err)
+		}
+		logger_.Info("Database connected and migrated")
+		repo = repository.NewRepository(db, cfg.DBQueryTimeout)
+	}
+
+	var rates exchange.RateProvider
+	if cfg.ExchangeRatesFile != "" {
+		fileRates, err := exchange.NewFileProvider(cfg.ExchangeRatesFile)
+		if err != nil {
+			logger_.Fatalf("failed to load exchange rates: %v", err)
+		}
+		rates = fileRates
+		logger_.Infof("Exchange rates loaded from %s", cfg.ExchangeRatesFile)
 	}
-	logger_.Info("Database connected and migrated")
 
-	repo := repository.NewRepository(db)
-	usc := usecase.New(repo)
+	// Превышение бюджета пока только пишется в лог
+	budgetAlerts := usecase.BudgetNotifierFunc(func(_ context.Context, alert model.BudgetAlert) {
+		logger_.WithFields(logrus.Fields{
+			"budget_id":       alert.Status.Budget.ID,
+			"user_id":         alert.Status.Budget.UserID,
+			"category":        alert.Status.Budget.Category,
+			"month":           alert.Status.Month.Format("01-2006"),
+			"spent":           alert.Status.Spent,
+			"limit":           alert.Status.Budget.Amount,
+			"currency":        alert.Status.Budget.Currency,
+			"subscription_id": alert.SubscriptionID,
+		}).Warn("Budget exceeded")
+	})
+
+	usc := usecase.New(repo, rates, budgetAlerts, []byte(cfg.CalendarSecret))
 	h := handler.New(usc)
 
 	r := gin.Default()
 	h.RegisterRoutes(r)
 
+	// Базовый контекст всех запросов: отменяется, если штатное завершение не уложилось в таймаут,
+	// чтобы прервать ещё выполняющиеся запросы к БД.
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
 	srv := &http.Server{
-		Addr:    ":8080",
-		Handler: r,
+		Addr:        ":8080",
+		Handler:     r,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+	}
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	if cfg.DeletedRetention > 0 {
+		go runPurge(purgeCtx, usc, cfg.DeletedRetention, cfg.PurgeInterval, logger_)
 	}
 
 	go func() {
@@ -55,12 +102,36 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger_.Info("Shutdown signal received, exiting...")
+	stopPurge()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
+		cancelBase()
 		logger_.Fatalf("Server forced to shutdown: %v", err)
 	}
 
 	logger_.Info("Server exiting gracefully")
 }
+
+// runPurge раз в interval окончательно удаляет подписки, мягко удалённые дольше retention назад.
+func runPurge(ctx context.Context, usc *usecase.Usecase, retention, interval time.Duration, log *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := usc.PurgeDeleted(ctx, retention)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Errorf("failed to purge deleted subscriptions: %v", err)
+		case purged > 0:
+			log.Infof("Purged %d deleted subscriptions", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -15,32 +15,68 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/subscriptions": {
+        "/audit": {
             "get": {
-                "description": "Get all subscriptions optionally filtered by user_id and service_name, with pagination",
+                "description": "Get subscription changes in chronological order, filtered by subscription, owner, actor, operation and time, with pagination",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "audit"
                 ],
-                "summary": "List subscriptions",
+                "summary": "Audit log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by user UUID",
+                        "description": "Filter by subscription UUID",
+                        "name": "subscription_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by subscription owner UUID",
                         "name": "user_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by service name",
-                        "name": "service_name",
+                        "description": "Filter by actor",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "delete",
+                            "restore",
+                            "cancel",
+                            "purge",
+                            "price_change",
+                            "price_change_delete",
+                            "pause",
+                            "resume"
+                        ],
+                        "type": "string",
+                        "description": "Filter by operation",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes at or after this time (RFC 3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes before this time (RFC 3339)",
+                        "name": "until",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 20,
+                        "default": 50,
                         "description": "Max number of records to return",
                         "name": "limit",
                         "in": "query"
@@ -55,34 +91,70 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated list of subscriptions",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/subscriptions_internal_model.AuditList"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets": {
+            "get": {
+                "description": "Get budgets, optionally only those of one user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "List budgets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.Budget"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Create a subscription with service name, price, user ID, start and optional end dates",
+                "description": "Set a monthly spending limit for a user, overall or for one subscription category",
                 "consumes": [
                     "application/json"
                 ],
@@ -90,17 +162,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Create a new subscription",
+                "summary": "Create a budget",
                 "parameters": [
                     {
-                        "description": "Subscription request body",
-                        "name": "subscription",
+                        "description": "Budget request body",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                            "$ref": "#/definitions/subscriptions_internal_model.BudgetReq"
                         }
                     }
                 ],
@@ -108,40 +180,40 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                            "$ref": "#/definitions/subscriptions_internal_model.Budget"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     }
                 }
             }
         },
-        "/subscriptions/total": {
+        "/budgets/status": {
             "get": {
-                "description": "Calculate total cost for a user and optional service within a date range (from, to in MM-YYYY format)",
+                "description": "Report how much of each budget of a user is used in a month",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Calculate total subscription cost",
+                "summary": "Progress of all user budgets",
                 "parameters": [
                     {
                         "type": "string",
@@ -152,20 +224,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Service name",
-                        "name": "service_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start period (MM-YYYY)",
-                        "name": "from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End period (MM-YYYY)",
-                        "name": "to",
+                        "description": "Month (MM-YYYY), default current month",
+                        "name": "month",
                         "in": "query"
                     }
                 ],
@@ -173,47 +233,40 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.BudgetStatus"
                             }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     }
                 }
             }
         },
-        "/subscriptions/{id}": {
+        "/budgets/{id}": {
             "get": {
-                "description": "Get details of a subscription by its UUID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Get subscription by ID",
+                "summary": "Get budget by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID (UUID)",
+                        "description": "Budget ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -223,31 +276,30 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                            "$ref": "#/definitions/subscriptions_internal_model.Budget"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     }
                 }
             },
             "put": {
-                "description": "Update subscription fields by subscription UUID",
                 "consumes": [
                     "application/json"
                 ],
@@ -255,24 +307,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Update subscription by ID",
+                "summary": "Update budget by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID (UUID)",
+                        "description": "Budget ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated subscription data",
-                        "name": "subscription",
+                        "description": "Updated budget data",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                            "$ref": "#/definitions/subscriptions_internal_model.BudgetReq"
                         }
                     }
                 ],
@@ -280,39 +332,44 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                            "$ref": "#/definitions/subscriptions_internal_model.Budget"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Delete subscription by UUID",
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Delete subscription by ID",
+                "summary": "Delete budget by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID (UUID)",
+                        "description": "Budget ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -325,79 +382,3155 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "subscriptions_internal_model.Subscription": {
-            "type": "object",
-            "properties": {
-                "end_date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "integer"
-                },
-                "service_name": {
-                    "type": "string"
-                },
-                "start_date": {
-                    "description": "формат \"07-2025\"",
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
+        },
+        "/budgets/{id}/status": {
+            "get": {
+                "description": "Report how much of the budget is used in a month: every charge of the month, including ones not yet made, converted to the budget currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Budget progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month (MM-YYYY), default current month",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.BudgetStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
                 }
             }
         },
-        "subscriptions_internal_model.SubscriptionReq": {
-            "type": "object",
-            "required": [
-                "price",
-                "service_name",
-                "start_date",
-                "user_id"
-            ],
-            "properties": {
-                "end_date": {
-                    "description": "EndDate optional subscription end date in MM-YYYY format",
-                    "type": "string"
-                },
-                "price": {
-                    "description": "Price subscription price, must be positive\nrequired: true",
-                    "type": "integer"
-                },
-                "service_name": {
-                    "description": "ServiceName is the name of the subscription service\nrequired: true",
-                    "type": "string"
-                },
-                "start_date": {
-                    "description": "StartDate subscription start date in MM-YYYY format\nrequired: true",
-                    "type": "string",
-                    "example": "07-2025"
-                },
-                "user_id": {
-                    "description": "UserID owner of the subscription (UUID)\nrequired: true",
-                    "type": "string"
+        "/services": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List the service catalog",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.Service"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a catalog entry with a canonical name and aliases. New subscriptions whose service_name matches an alias (case- and whitespace-insensitive) are linked to it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Add a service to the catalog",
+                "parameters": [
+                    {
+                        "description": "Service request body",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.ServiceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get catalog service by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the fields and aliases of a catalog entry. Existing subscriptions are not renamed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Update catalog service by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated service data",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.ServiceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a catalog entry. Linked subscriptions are kept and lose the reference",
+                "tags": [
+                    "services"
+                ],
+                "summary": "Delete catalog service by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions": {
+            "get": {
+                "description": "Get all subscriptions optionally filtered by user_id, service_name, category and tag, with pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only subscriptions active and not paused in this month (MM-YYYY)",
+                        "name": "active_in",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include soft-deleted subscriptions",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Max number of records to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of subscriptions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a subscription with service name, price, user ID, start and optional end dates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create a new subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription request body",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create the subscription even if the user already has a similar one",
+                        "name": "allow_duplicate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The subscription looks like a duplicate",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/batch": {
+            "post": {
+                "description": "Apply a list of operations in order. In atomic mode (default) all operations run in one transaction and the first failure rolls back the whole batch and is returned as the error, with the operation index in the detail. In best_effort mode every operation is applied independently and gets its own result",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create, update and delete subscriptions in one request",
+                "parameters": [
+                    {
+                        "description": "Operations",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.BatchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/cancellations": {
+            "get": {
+                "description": "Count canceled subscriptions per reason code, optionally only those canceled within from..to (MM-YYYY, both months inclusive)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cancellations"
+                ],
+                "summary": "Cancellation reasons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First month of cancellation (MM-YYYY)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last month of cancellation (MM-YYYY)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.CancellationStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/export": {
+            "get": {
+                "description": "Stream every subscription matching the list filters, without pagination, as CSV, JSON Lines or XLSX. With totals=true the file also gets a summary for the from/to period: a second table after an empty line in CSV, a last {\"summary\": ...} line in JSON Lines, or a Totals sheet in XLSX",
+                "produces": [
+                    "text/csv",
+                    "application/jsonl",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Export subscriptions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only subscriptions active and not paused in this month (MM-YYYY)",
+                        "name": "active_in",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include soft-deleted subscriptions",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Append a totals summary",
+                        "name": "totals",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the totals period (MM-YYYY), required with totals",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the totals period (MM-YYYY), required with totals",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert the totals to",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/forecast": {
+            "get": {
+                "description": "Project spend month by month for the next N months, starting with the rest of the current month, from billing cycles, end dates, pauses and scheduled price changes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Spending forecast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 12,
+                        "description": "Number of months to forecast",
+                        "name": "months",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert amounts to",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.ForecastMonth"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/import": {
+            "post": {
+                "description": "Import subscriptions from a CSV file with a header row. Every row is validated like POST /subscriptions; if any row fails, nothing is saved and the row errors are returned with status 422. With dry_run=true the file is only validated. Columns are matched to fields by name unless mapping is given; tags are comma-separated within a cell",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Import subscriptions from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object mapping subscription fields to CSV column names, e.g. {\\",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": ",",
+                        "description": "Column delimiter",
+                        "name": "delimiter",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner for rows without a user_id column",
+                        "name": "user_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate only, do not save",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.ImportResult"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.ImportResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/statement": {
+            "post": {
+                "description": "Upload an OFX or CSV bank statement and get recurring charges that look like subscriptions: the same merchant, a roughly constant amount and a weekly, monthly, quarterly or yearly interval. Each candidate holds a subscription request with the catalog service name where the merchant matches one. Nothing is saved; send the chosen candidates to /subscriptions/statement/confirm. A CSV statement needs a header with date, amount (charges negative) and payee columns",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Find subscriptions in a bank statement",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "OFX or CSV statement",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner of the proposed subscriptions",
+                        "name": "user_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "ofx",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Statement format, detected from the content when omitted",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of transactions without one in the statement (default RUB)",
+                        "name": "currency",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": ",",
+                        "description": "CSV column delimiter",
+                        "name": "delimiter",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV date format, e.g. DD/MM/YYYY; common formats are detected when omitted",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object mapping date, amount, payee and currency to CSV column names, e.g. {\\",
+                        "name": "mapping",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.StatementAnalysis"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/statement/confirm": {
+            "post": {
+                "description": "Create the subscriptions chosen from /subscriptions/statement candidates, possibly edited, with the same rules as POST /subscriptions, except that duplicates are not checked: candidates similar to recorded subscriptions already carry existing_subscription_id. All of them are created in one transaction: if one fails, none are saved and the error detail names its index",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create subscriptions from statement candidates",
+                "parameters": [
+                    {
+                        "description": "Chosen subscriptions",
+                        "name": "candidates",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.ConfirmCandidatesReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/total": {
+            "get": {
+                "description": "Calculate total cost of charges made for a user and optional service within a date range (from, to in MM-YYYY format, both months inclusive)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Calculate total subscription cost",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start period (MM-YYYY)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End period (MM-YYYY)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert the total to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "category"
+                        ],
+                        "type": "string",
+                        "description": "Group totals; category returns an array of model.CategoryTotal",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Total"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/total/breakdown": {
+            "get": {
+                "description": "Split the total cost within a date range (from, to in MM-YYYY format) per month and per service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Monthly breakdown of totals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start period (MM-YYYY)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End period (MM-YYYY)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert amounts to",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.BreakdownItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/trials": {
+            "get": {
+                "description": "Get subscriptions whose free trial ends within the next N days, followed by a paid charge",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List trials ending soon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 7,
+                        "description": "Look-ahead window in days",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/upcoming": {
+            "get": {
+                "description": "Get every charge expected in the next N days, derived from start date, billing cycle, end date, trials, pauses and price changes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List upcoming charges",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user UUID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service catalog entry (UUID)",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category, empty value selects subscriptions without a category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Look-ahead window in days",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.Charge"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}": {
+            "get": {
+                "description": "Get details of a subscription by its UUID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get subscription by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Subscription version, pass it in If-Match to update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update subscription fields by subscription UUID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Update subscription by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated subscription data",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous GET; the update is rejected if the subscription has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Subscription version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft-delete subscription by UUID. It disappears from lists and forecasts, past charges stay in totals until purged",
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Delete subscription by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Apply an RFC 7396 JSON merge patch to the subscription fields of SubscriptionReq. Omitted fields keep their values, null removes a field (e.g. end_date), arrays such as tags are replaced as a whole. The merged result is validated like a new subscription",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Partially update subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch with the fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous GET; the update is rejected if the subscription has changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Subscription version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/cancel": {
+            "post": {
+                "description": "Cancel a subscription from the given month or at the end of the current paid period (default). The record is kept for history and analytics",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cancellations"
+                ],
+                "summary": "Cancel a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation parameters",
+                        "name": "cancel",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.CancelReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/history": {
+            "get": {
+                "description": "Get every recorded change of a subscription in chronological order, also after it has been deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Subscription change history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.AuditRecord"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/pause": {
+            "post": {
+                "description": "Pause a subscription from the given month (default current month). Paused months are not charged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pauses"
+                ],
+                "summary": "Pause a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pause parameters",
+                        "name": "pause",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.PauseReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Pause"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/pauses": {
+            "get": {
+                "description": "Get the pause history of a subscription ordered by start month",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pauses"
+                ],
+                "summary": "List pauses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.Pause"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/prices": {
+            "get": {
+                "description": "Get the price history of a subscription ordered by effective date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "List price changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.PriceChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Set a new subscription price effective from the given month. Charges before that month keep the previous price",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Schedule a price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Price change",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.PriceChangeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.PriceChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/prices/{price_id}": {
+            "delete": {
+                "description": "Remove a scheduled or recorded price change",
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Delete a price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price change ID (UUID)",
+                        "name": "price_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/restore": {
+            "post": {
+                "description": "Undo a soft delete that has not been purged yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Restore a deleted subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/resume": {
+            "post": {
+                "description": "Close the open pause of a subscription. Charges resume from the given month",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pauses"
+                ],
+                "summary": "Resume a paused subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resume parameters",
+                        "name": "resume",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.ResumeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions_internal_model.Pause"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/calendar.ics": {
+            "get": {
+                "description": "RFC 5545 feed with an all-day recurring event for every active subscription, derived from the start date, billing cycle and end date. Charges skipped by pauses are excluded and scheduled price changes start a new series",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Calendar feed of upcoming charges",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signed token from the calendar link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Calendar feed is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/insights/duplicates": {
+            "get": {
+                "description": "Find pairs of the user's subscriptions that are active at the same time and either belong to the same service (same name ignoring case and spaces, or the same catalog entry) or have near-identical names and prices",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "insights"
+                ],
+                "summary": "Likely duplicate subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscriptions_internal_model.Duplicate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "internal_handler.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error problem details for a failed operation",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.Problem"
+                        }
+                    ]
+                },
+                "index": {
+                    "description": "Index position of the operation in the request",
+                    "type": "integer"
+                },
+                "op": {
+                    "$ref": "#/definitions/subscriptions_internal_model.BatchOp"
+                },
+                "status": {
+                    "description": "Status HTTP status the operation would get as a single request",
+                    "type": "integer",
+                    "example": 201
+                },
+                "subscription": {
+                    "description": "Subscription saved subscription for successful create and update",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                        }
+                    ]
+                }
+            }
+        },
+        "internal_handler.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "$ref": "#/definitions/subscriptions_internal_model.BatchMode"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.BatchItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "Detail описание конкретной ошибки",
+                    "type": "string",
+                    "example": "invalid UUID"
+                },
+                "field": {
+                    "description": "Field поле запроса, не прошедшее проверку",
+                    "type": "string",
+                    "example": "start_date"
+                },
+                "instance": {
+                    "description": "Instance путь запроса, на котором возникла ошибка",
+                    "type": "string",
+                    "example": "/subscriptions/123"
+                },
+                "status": {
+                    "description": "Status HTTP-код ответа",
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "description": "Title краткое описание типа ошибки",
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
+                    "description": "Type URI, идентифицирующий тип ошибки",
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "subscriptions_internal_model.AuditList": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscriptions_internal_model.AuditRecord"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "subscriptions_internal_model.AuditOperation": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore",
+                "cancel",
+                "purge",
+                "price_change",
+                "price_change_delete",
+                "pause",
+                "resume"
+            ],
+            "x-enum-varnames": [
+                "AuditCreate",
+                "AuditUpdate",
+                "AuditDelete",
+                "AuditRestore",
+                "AuditCancel",
+                "AuditPurge",
+                "AuditPriceChange",
+                "AuditPriceChangeDelete",
+                "AuditPause",
+                "AuditResume"
+            ]
+        },
+        "subscriptions_internal_model.AuditRecord": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Changes изменённые поля подписки в JSON-представлении: значения до и после",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/subscriptions_internal_model.FieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "operation": {
+                    "$ref": "#/definitions/subscriptions_internal_model.AuditOperation"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.BatchMode": {
+            "type": "string",
+            "enum": [
+                "atomic",
+                "best_effort"
+            ],
+            "x-enum-varnames": [
+                "BatchAtomic",
+                "BatchBestEffort"
+            ]
+        },
+        "subscriptions_internal_model.BatchOp": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "BatchCreate",
+                "BatchUpdate",
+                "BatchDelete"
+            ]
+        },
+        "subscriptions_internal_model.BatchOperationReq": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID subscription to update or delete",
+                    "type": "string"
+                },
+                "op": {
+                    "description": "Op operation kind\nrequired: true",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.BatchOp"
+                        }
+                    ],
+                    "example": "create"
+                },
+                "subscription": {
+                    "description": "Subscription full subscription data for create and update",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                        }
+                    ]
+                },
+                "version": {
+                    "description": "Version optional expected subscription version for update, like If-Match",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "subscriptions_internal_model.BatchReq": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "mode": {
+                    "description": "Mode atomic applies all operations or none (default), best_effort applies each independently",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.BatchMode"
+                        }
+                    ],
+                    "example": "atomic"
+                },
+                "operations": {
+                    "description": "Operations operations in the order they are applied\nrequired: true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscriptions_internal_model.BatchOperationReq"
+                    }
+                }
+            }
+        },
+        "subscriptions_internal_model.BillingPeriod": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month",
+                "quarter",
+                "year"
+            ],
+            "x-enum-varnames": [
+                "BillingDay",
+                "BillingWeek",
+                "BillingMonth",
+                "BillingQuarter",
+                "BillingYear"
+            ]
+        },
+        "subscriptions_internal_model.BreakdownItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "в минимальных единицах валюты",
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "month": {
+                    "description": "первое число месяца",
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "в минимальных единицах валюты бюджета",
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency код валюты ISO 4217, в которую переводятся списания при сравнении с лимитом",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.BudgetReq": {
+            "type": "object",
+            "required": [
+                "amount",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount monthly limit in minor currency units\nrequired: true",
+                    "type": "integer",
+                    "example": 150000
+                },
+                "category": {
+                    "description": "Category subscription category the budget applies to, empty for an overall budget",
+                    "type": "string",
+                    "example": "entertainment"
+                },
+                "currency": {
+                    "description": "Currency ISO 4217 currency code of the limit (default RUB)",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "user_id": {
+                    "description": "UserID owner of the budget (UUID)\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "$ref": "#/definitions/subscriptions_internal_model.Budget"
+                },
+                "exceeded": {
+                    "type": "boolean"
+                },
+                "month": {
+                    "description": "первое число месяца",
+                    "type": "string"
+                },
+                "percent": {
+                    "description": "доля израсходованного лимита, %",
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "отрицательный, если лимит превышен",
+                    "type": "integer"
+                },
+                "spent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "subscriptions_internal_model.CancelReason": {
+            "type": "string",
+            "enum": [
+                "too_expensive",
+                "not_using",
+                "switched_service",
+                "missing_features",
+                "technical_issues",
+                "temporary",
+                "other",
+                "unspecified"
+            ],
+            "x-enum-varnames": [
+                "CancelTooExpensive",
+                "CancelNotUsing",
+                "CancelSwitchedService",
+                "CancelMissingFeatures",
+                "CancelTechnicalIssues",
+                "CancelTemporary",
+                "CancelOther",
+                "CancelUnspecified"
+            ]
+        },
+        "subscriptions_internal_model.CancelReq": {
+            "type": "object",
+            "properties": {
+                "effective_date": {
+                    "description": "EffectiveDate last active month in MM-YYYY format, or end_of_period to stop before the next paid charge (default)",
+                    "type": "string",
+                    "example": "end_of_period"
+                },
+                "reason": {
+                    "description": "Reason optional cancellation reason code",
+                    "enum": [
+                        "too_expensive",
+                        "not_using",
+                        "switched_service",
+                        "missing_features",
+                        "technical_issues",
+                        "temporary",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.CancelReason"
+                        }
+                    ],
+                    "example": "too_expensive"
+                }
+            }
+        },
+        "subscriptions_internal_model.CancellationStats": {
+            "type": "object",
+            "properties": {
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscriptions_internal_model.ReasonCount"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "subscriptions_internal_model.Charge": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "в минимальных единицах валюты",
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.ConfirmCandidatesReq": {
+            "type": "object",
+            "required": [
+                "subscriptions"
+            ],
+            "properties": {
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                    }
+                }
+            }
+        },
+        "subscriptions_internal_model.Duplicate": {
+            "type": "object",
+            "properties": {
+                "overlap_from": {
+                    "description": "OverlapFrom первое число первого месяца, в котором действуют обе подписки",
+                    "type": "string"
+                },
+                "overlap_to": {
+                    "description": "OverlapTo первое число последнего общего месяца, пусто — обе подписки бессрочные",
+                    "type": "string"
+                },
+                "reason": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.DuplicateReason"
+                        }
+                    ],
+                    "example": "same_service"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscriptions_internal_model.Subscription"
+                    }
+                }
+            }
+        },
+        "subscriptions_internal_model.DuplicateReason": {
+            "type": "string",
+            "enum": [
+                "same_service",
+                "similar"
+            ],
+            "x-enum-varnames": [
+                "DuplicateSameService",
+                "DuplicateSimilar"
+            ]
+        },
+        "subscriptions_internal_model.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "subscriptions_internal_model.ForecastMonth": {
+            "type": "object",
+            "properties": {
+                "by_currency": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "month": {
+                    "description": "первое число месяца",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "subscriptions_internal_model.ImportResult": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscriptions_internal_model.ImportRowError"
+                    }
+                },
+                "imported": {
+                    "description": "Imported число сохранённых подписок",
+                    "type": "integer"
+                },
+                "rows": {
+                    "description": "Rows число строк с данными",
+                    "type": "integer"
+                },
+                "valid": {
+                    "description": "Valid число строк, прошедших проверку",
+                    "type": "integer"
+                }
+            }
+        },
+        "subscriptions_internal_model.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "start_date"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid start_date format, expected MM-YYYY"
+                },
+                "row": {
+                    "description": "Row номер строки файла, заголовок — строка 1",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "subscriptions_internal_model.Pause": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "paused_from": {
+                    "type": "string"
+                },
+                "resumed_at": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.PauseReq": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "From first paused month in MM-YYYY format (default current month)",
+                    "type": "string",
+                    "example": "08-2025"
+                }
+            }
+        },
+        "subscriptions_internal_model.PriceChange": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "в минимальных единицах валюты подписки",
+                    "type": "integer"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.PriceChangeReq": {
+            "type": "object",
+            "required": [
+                "effective_from",
+                "price"
+            ],
+            "properties": {
+                "effective_from": {
+                    "description": "EffectiveFrom month from which the new price applies, in MM-YYYY format\nrequired: true",
+                    "type": "string",
+                    "example": "01-2026"
+                },
+                "price": {
+                    "description": "Price new price in minor currency units of the subscription\nrequired: true",
+                    "type": "integer",
+                    "example": 49900
+                }
+            }
+        },
+        "subscriptions_internal_model.ReasonCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "$ref": "#/definitions/subscriptions_internal_model.CancelReason"
+                }
+            }
+        },
+        "subscriptions_internal_model.ResumeReq": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "description": "At first month charged again in MM-YYYY format (default current month, or the month after the pause start)",
+                    "type": "string",
+                    "example": "11-2025"
+                }
+            }
+        },
+        "subscriptions_internal_model.Service": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Aliases нормализованные псевдонимы, всегда включают само название; хранятся в service_aliases",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "default_category": {
+                    "description": "DefaultCategory категория для новых подписок, если она не указана явно",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.ServiceReq": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "aliases": {
+                    "description": "Aliases alternative spellings matched case- and whitespace-insensitively",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "netflix.com",
+                        "нетфликс"
+                    ]
+                },
+                "default_category": {
+                    "description": "DefaultCategory category assigned to new subscriptions of this service",
+                    "type": "string",
+                    "example": "entertainment"
+                },
+                "logo_url": {
+                    "description": "LogoURL service logo image",
+                    "type": "string",
+                    "example": "https://www.netflix.com/favicon.ico"
+                },
+                "name": {
+                    "description": "Name canonical service name\nrequired: true",
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "url": {
+                    "description": "URL service website",
+                    "type": "string",
+                    "example": "https://www.netflix.com"
+                }
+            }
+        },
+        "subscriptions_internal_model.StatementAnalysis": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscriptions_internal_model.StatementCandidate"
+                    }
+                },
+                "transactions": {
+                    "description": "Transactions number of transactions in the statement",
+                    "type": "integer"
+                }
+            }
+        },
+        "subscriptions_internal_model.StatementCandidate": {
+            "type": "object",
+            "properties": {
+                "charges": {
+                    "description": "Charges dates of the matching charges",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ended": {
+                    "description": "Ended charges stopped more than a billing period before the end of the statement, end_date is set to the last charge",
+                    "type": "boolean"
+                },
+                "existing_subscription_id": {
+                    "description": "ExistingSubscriptionID user's subscription with the same service name, the candidate is probably already recorded",
+                    "type": "string"
+                },
+                "merchant": {
+                    "description": "Merchant payee as it appears in the latest matching transaction",
+                    "type": "string",
+                    "example": "NETFLIX.COM 866-579-7172"
+                },
+                "subscription": {
+                    "description": "Subscription proposed subscription; edit it if needed and send it to /subscriptions/statement/confirm",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.SubscriptionReq"
+                        }
+                    ]
+                }
+            }
+        },
+        "subscriptions_internal_model.Subscription": {
+            "type": "object",
+            "properties": {
+                "billing_interval": {
+                    "type": "integer"
+                },
+                "billing_period": {
+                    "description": "BillingPeriod и BillingInterval задают цикл списаний: раз в BillingInterval периодов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/subscriptions_internal_model.BillingPeriod"
+                        }
+                    ]
+                },
+                "cancel_reason": {
+                    "$ref": "#/definitions/subscriptions_internal_model.CancelReason"
+                },
+                "canceled_at": {
+                    "description": "CanceledAt момент отмены; подписка при этом хранится, а EndDate задаёт последний месяц",
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Category категория расходов в нижнем регистре, пусто — без категории",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency код валюты ISO 4217",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt момент мягкого удаления: подписка скрыта из списков, но её прошлые\nсписания остаются в суммах, пока запись не удалена окончательно",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "в минимальных единицах валюты (копейки, центы)",
+                    "type": "integer"
+                },
+                "service_id": {
+                    "description": "ServiceID запись каталога сервисов, с которой связана подписка",
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "description": "формат \"07-2025\"",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags имена меток по алфавиту, хранятся в таблицах tags и subscription_tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "trial_end_date": {
+                    "description": "TrialEndDate первый платный день: списания до этой даты бесплатны",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version увеличивается при каждом изменении подписки, служит ETag для оптимистичных блокировок",
+                    "type": "integer"
+                }
+            }
+        },
+        "subscriptions_internal_model.SubscriptionReq": {
+            "type": "object",
+            "required": [
+                "price",
+                "service_name",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "billing_interval": {
+                    "description": "BillingInterval number of periods between charges, e.g. 2 with week means every two weeks (default 1, at most about ten years)",
+                    "type": "integer",
+                    "example": 1
+                },
+                "billing_period": {
+                    "description": "BillingPeriod billing period unit: day, week, month, quarter or year (default month)",
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "week",
+                        "month",
+                        "quarter",
+                        "year"
+                    ],
+                    "example": "month"
+                },
+                "category": {
+                    "description": "Category optional spending category, e.g. entertainment (case-insensitive)",
+                    "type": "string",
+                    "example": "entertainment"
+                },
+                "currency": {
+                    "description": "Currency ISO 4217 currency code of the price (default RUB)",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "description": "EndDate optional subscription end date in MM-YYYY format",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Price subscription price in minor currency units (e.g. kopecks or cents), must be positive\nrequired: true",
+                    "type": "integer",
+                    "example": 39900
+                },
+                "service_id": {
+                    "description": "ServiceID optional service catalog entry; when omitted, service_name is matched against catalog aliases",
+                    "type": "string"
+                },
+                "service_name": {
+                    "description": "ServiceName is the name of the subscription service\nrequired: true",
+                    "type": "string"
+                },
+                "start_date": {
+                    "description": "StartDate subscription start date in MM-YYYY format\nrequired: true",
+                    "type": "string",
+                    "example": "07-2025"
+                },
+                "tags": {
+                    "description": "Tags optional free-form labels (case-insensitive)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "family",
+                        "work"
+                    ]
+                },
+                "trial_end_date": {
+                    "description": "TrialEndDate optional first paid month in MM-YYYY format, charges before it are free",
+                    "type": "string",
+                    "example": "08-2025"
+                },
+                "trial_months": {
+                    "description": "TrialMonths optional free trial length in months from start_date, alternative to trial_end_date",
+                    "type": "integer",
+                    "example": 1
+                },
+                "user_id": {
+                    "description": "UserID owner of the subscription (UUID)\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "subscriptions_internal_model.Total": {
+            "type": "object",
+            "properties": {
+                "by_currency": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         }
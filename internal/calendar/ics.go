@@ -0,0 +1,147 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"subscriptions/internal/exchange"
+	"subscriptions/internal/model"
+)
+
+// ContentType MIME-тип ленты iCalendar.
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+	// maxLineOctets предел длины строки по RFC 5545, длинные строки переносятся
+	maxLineOctets = 75
+)
+
+// Write пишет календарь RFC 5545 с событием на весь день для каждой серии списаний.
+// Повторения задаются правилом RRULE, пропуски из-за пауз — EXDATE, а за день
+// до списания срабатывает напоминание.
+func Write(w io.Writer, series []model.RenewalSeries, now time.Time) error {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//subscriptions//renewals//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	cw.line("X-WR-CALNAME:" + escape("Subscription renewals"))
+	cw.line("REFRESH-INTERVAL;VALUE=DURATION:PT12H")
+	cw.line("X-PUBLISHED-TTL:PT12H")
+	stamp := now.UTC().Format(dateTimeFormat)
+	for _, s := range series {
+		amount := formatAmount(s.Amount, s.Currency)
+		cw.line("BEGIN:VEVENT")
+		cw.line("UID:" + uid(s))
+		cw.line("DTSTAMP:" + stamp)
+		cw.line("DTSTART;VALUE=DATE:" + s.Start.Format(dateFormat))
+		cw.line("RRULE:" + rrule(s))
+		if len(s.Except) > 0 {
+			dates := make([]string, len(s.Except))
+			for i, at := range s.Except {
+				dates[i] = at.Format(dateFormat)
+			}
+			cw.line("EXDATE;VALUE=DATE:" + strings.Join(dates, ","))
+		}
+		cw.line("SUMMARY:" + escape(fmt.Sprintf("%s: %s", s.ServiceName, amount)))
+		cw.line("DESCRIPTION:" + escape(fmt.Sprintf("Subscription renewal, %s %s.", amount, every(s))))
+		cw.line("TRANSP:TRANSPARENT")
+		cw.line("BEGIN:VALARM")
+		cw.line("ACTION:DISPLAY")
+		cw.line("DESCRIPTION:" + escape(fmt.Sprintf("%s renews tomorrow: %s", s.ServiceName, amount)))
+		cw.line("TRIGGER:-P1D")
+		cw.line("END:VALARM")
+		cw.line("END:VEVENT")
+	}
+	cw.line("END:VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// uid постоянный идентификатор серии: подписка и, для серий после изменения цены, дата нового тарифа.
+func uid(s model.RenewalSeries) string {
+	if s.PriceFrom == nil {
+		return s.SubscriptionID.String() + "@subscriptions"
+	}
+	return s.SubscriptionID.String() + "-" + s.PriceFrom.Format(dateFormat) + "@subscriptions"
+}
+
+func rrule(s model.RenewalSeries) string {
+	freq, interval := "MONTHLY", s.Interval
+	switch s.Period {
+	case model.BillingDay:
+		freq = "DAILY"
+	case model.BillingWeek:
+		freq = "WEEKLY"
+	case model.BillingQuarter:
+		interval *= 3
+	case model.BillingYear:
+		freq = "YEARLY"
+	}
+	rule := "FREQ=" + freq + ";INTERVAL=" + strconv.Itoa(interval)
+	if s.Until != nil {
+		rule += ";UNTIL=" + s.Until.Format(dateFormat)
+	}
+	return rule
+}
+
+func every(s model.RenewalSeries) string {
+	unit := string(s.Period)
+	if s.Interval == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", s.Interval, unit)
+}
+
+// formatAmount записывает сумму в минимальных единицах в основных: 79900 RUB — 799.00 RUB.
+func formatAmount(amount int, currency string) string {
+	digits := exchange.MinorUnits(currency)
+	value := float64(amount) / math.Pow10(digits)
+	return strconv.FormatFloat(value, 'f', digits, 64) + " " + currency
+}
+
+// escape экранирует текстовое значение свойства (RFC 5545, 3.3.11).
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// contentWriter пишет строки с CRLF и переносит длинные строки, не разрывая символы UTF-8.
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *contentWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		cw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Продолжение начинается с пробела, который тоже занимает октет
+		limit = maxLineOctets - 1
+	}
+	cw.write(s + "\r\n")
+}
+
+func (cw *contentWriter) write(s string) {
+	if cw.err == nil {
+		_, cw.err = cw.w.WriteString(s)
+	}
+}
@@ -0,0 +1,132 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRRule(t *testing.T) {
+	until := date(2026, 6, 1)
+	tests := []struct {
+		name   string
+		series model.RenewalSeries
+		want   string
+	}{
+		{
+			name:   "monthly",
+			series: model.RenewalSeries{Period: model.BillingMonth, Interval: 1},
+			want:   "FREQ=MONTHLY;INTERVAL=1",
+		},
+		{
+			name:   "quarter in months",
+			series: model.RenewalSeries{Period: model.BillingQuarter, Interval: 2},
+			want:   "FREQ=MONTHLY;INTERVAL=6",
+		},
+		{
+			name:   "daily",
+			series: model.RenewalSeries{Period: model.BillingDay, Interval: 10},
+			want:   "FREQ=DAILY;INTERVAL=10",
+		},
+		{
+			name:   "weekly with until",
+			series: model.RenewalSeries{Period: model.BillingWeek, Interval: 2, Until: &until},
+			want:   "FREQ=WEEKLY;INTERVAL=2;UNTIL=20260601",
+		},
+		{
+			name:   "yearly with until",
+			series: model.RenewalSeries{Period: model.BillingYear, Interval: 1, Until: &until},
+			want:   "FREQ=YEARLY;INTERVAL=1;UNTIL=20260601",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rrule(tt.series))
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `a\;b\,c\\d\ne\nf`, escape("a;b,c\\d\r\ne\nf"))
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "799.00 RUB", formatAmount(79900, "RUB"))
+	assert.Equal(t, "1500 JPY", formatAmount(1500, "JPY"))
+}
+
+func TestWrite(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	until := date(2026, 3, 1)
+	priceFrom := date(2025, 11, 1)
+	series := []model.RenewalSeries{{
+		SubscriptionID: id,
+		ServiceName:    "Netflix, Premium",
+		Amount:         79900,
+		Currency:       "RUB",
+		Start:          date(2025, 11, 1),
+		Period:         model.BillingMonth,
+		Interval:       1,
+		Until:          &until,
+		Except:         []time.Time{date(2025, 12, 1), date(2026, 1, 1)},
+		PriceFrom:      &priceFrom,
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, series, date(2025, 10, 17)))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	for _, line := range []string{
+		"UID:11111111-1111-1111-1111-111111111111-20251101@subscriptions",
+		"DTSTAMP:20251017T000000Z",
+		"DTSTART;VALUE=DATE:20251101",
+		"RRULE:FREQ=MONTHLY;INTERVAL=1;UNTIL=20260301",
+		"EXDATE;VALUE=DATE:20251201,20260101",
+		`SUMMARY:Netflix\, Premium: 799.00 RUB`,
+		"TRIGGER:-P1D",
+	} {
+		assert.Contains(t, out, "\r\n"+line+"\r\n")
+	}
+}
+
+// TestWriteFolding проверяет перенос длинных строк: не длиннее 75 октетов,
+// продолжение с пробела, символы UTF-8 не разрываются.
+func TestWriteFolding(t *testing.T) {
+	name := strings.Repeat("Подписка на онлайн-кинотеатр ", 5)
+	series := []model.RenewalSeries{{
+		SubscriptionID: uuid.New(),
+		ServiceName:    name,
+		Amount:         100,
+		Currency:       "RUB",
+		Start:          date(2025, 11, 1),
+		Period:         model.BillingMonth,
+		Interval:       1,
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, series, date(2025, 10, 17)))
+
+	raw := strings.TrimSuffix(buf.String(), "\r\n")
+	for _, line := range strings.Split(raw, "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+		assert.True(t, utf8.ValidString(line), "line %q splits a character", line)
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	assert.Contains(t, unfolded, "\r\nSUMMARY:"+name+": 1.00 RUB\r\n")
+	assert.Contains(t, buf.String(), "\r\n ")
+}
@@ -0,0 +1,24 @@
+package calendar
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/google/uuid"
+)
+
+// Token подписывает ссылку на календарь пользователя: HMAC-SHA256 от его id.
+// Календарные приложения не умеют передавать заголовки авторизации, поэтому
+// доступ к ленте даёт знание токена; без secret подобрать его нельзя.
+func Token(secret []byte, userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("calendar:"))
+	mac.Write(userID[:])
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify сравнивает token с подписью userID за постоянное время.
+func Verify(secret []byte, userID uuid.UUID, token string) bool {
+	return hmac.Equal([]byte(token), []byte(Token(secret, userID)))
+}
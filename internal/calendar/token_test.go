@@ -0,0 +1,19 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	user := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	token := Token(secret, user)
+
+	assert.True(t, Verify(secret, user, token))
+	assert.False(t, Verify([]byte("other"), user, token))
+	assert.False(t, Verify(secret, uuid.MustParse("22222222-2222-2222-2222-222222222222"), token))
+	assert.False(t, Verify(secret, user, ""))
+}
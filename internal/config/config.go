@@ -3,29 +3,95 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// minCalendarSecret минимальная длина ключа подписи календаря
+const minCalendarSecret = 32
+
 type Config struct {
 	AppPort string
+	Storage string
 	DBHost  string
 	DBPort  string
 	DBUser  string
 	DBPass  string
 	DBName  string
+	// DBQueryTimeout ограничивает время выполнения одного запроса к БД, 0 — без ограничения
+	DBQueryTimeout time.Duration
+	// ExchangeRatesFile путь к JSON-файлу с курсами валют, пусто — перевод валют отключён
+	ExchangeRatesFile string
+	// DeletedRetention сколько хранить мягко удалённые подписки, 0 — не удалять окончательно
+	DeletedRetention time.Duration
+	// PurgeInterval период фоновой очистки удалённых подписок
+	PurgeInterval time.Duration
+	// CalendarSecret ключ подписи ссылок на календарь списаний, пусто — календарь отключён
+	CalendarSecret string
 }
 
 func LoadConfig(_ string) (*Config, error) {
 	cfg := &Config{
 		AppPort: os.Getenv("APP_PORT"),
+		Storage: os.Getenv("STORAGE"),
 		DBHost:  os.Getenv("DB_HOST"),
 		DBPort:  os.Getenv("DB_PORT"),
 		DBUser:  os.Getenv("DB_USER"),
 		DBPass:  os.Getenv("DB_PASSWORD"),
 		DBName:  os.Getenv("DB_NAME"),
+
+		ExchangeRatesFile: os.Getenv("EXCHANGE_RATES_FILE"),
+		CalendarSecret:    os.Getenv("CALENDAR_SECRET"),
+	}
+
+	if v := os.Getenv("DB_QUERY_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid DB_QUERY_TIMEOUT %q, expected duration like 5s", v)
+		}
+		cfg.DBQueryTimeout = d
+	}
+
+	if v := os.Getenv("DELETED_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid DELETED_RETENTION %q, expected duration like 720h", v)
+		}
+		cfg.DeletedRetention = d
+	}
+
+	cfg.PurgeInterval = time.Hour
+	if v := os.Getenv("PURGE_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid PURGE_INTERVAL %q, expected duration like 1h", v)
+		}
+		cfg.PurgeInterval = d
+	}
+
+	if cfg.CalendarSecret != "" && len(cfg.CalendarSecret) < minCalendarSecret {
+		return nil, fmt.Errorf("CALENDAR_SECRET must be at least %d characters", minCalendarSecret)
+	}
+
+	if cfg.Storage == "" {
+		cfg.Storage = StoragePostgres
 	}
 
-	if cfg.AppPort == "" || cfg.DBHost == "" || cfg.DBPort == "" || cfg.DBUser == "" || cfg.DBPass == "" || cfg.DBName == "" {
-		return nil, fmt.Errorf("missing required environment variables")
+	switch cfg.Storage {
+	case StoragePostgres:
+		if cfg.AppPort == "" || cfg.DBHost == "" || cfg.DBPort == "" || cfg.DBUser == "" || cfg.DBPass == "" || cfg.DBName == "" {
+			return nil, fmt.Errorf("missing required environment variables")
+		}
+	case StorageMemory:
+		if cfg.AppPort == "" {
+			return nil, fmt.Errorf("missing required environment variables")
+		}
+	default:
+		return nil, fmt.Errorf("unknown STORAGE %q, expected %q or %q", cfg.Storage, StoragePostgres, StorageMemory)
 	}
 
 	return cfg, nil
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	postgres := map[string]string{
+		"APP_PORT": "8080", "DB_HOST": "db", "DB_PORT": "5432",
+		"DB_USER": "postgres", "DB_PASSWORD": "postgres", "DB_NAME": "subscriptions",
+	}
+	with := func(base map[string]string, extra ...string) map[string]string {
+		env := make(map[string]string, len(base)+len(extra)/2)
+		for k, v := range base {
+			env[k] = v
+		}
+		for i := 0; i < len(extra); i += 2 {
+			env[extra[i]] = extra[i+1]
+		}
+		return env
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(t *testing.T, cfg *Config)
+		wantErr string
+	}{
+		{
+			name: "postgres by default",
+			env:  postgres,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, StoragePostgres, cfg.Storage)
+				assert.Zero(t, cfg.DBQueryTimeout)
+			},
+		},
+		{
+			name:    "postgres without database settings",
+			env:     map[string]string{"APP_PORT": "8080"},
+			wantErr: "missing required environment variables",
+		},
+		{
+			name: "memory needs only port",
+			env:  map[string]string{"APP_PORT": "8080", "STORAGE": "memory"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, StorageMemory, cfg.Storage)
+			},
+		},
+		{
+			name:    "unknown storage",
+			env:     map[string]string{"APP_PORT": "8080", "STORAGE": "redis"},
+			wantErr: `unknown STORAGE "redis", expected "postgres" or "memory"`,
+		},
+		{
+			name: "query timeout",
+			env:  with(postgres, "DB_QUERY_TIMEOUT", "5s"),
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 5*time.Second, cfg.DBQueryTimeout)
+			},
+		},
+		{
+			name:    "invalid query timeout",
+			env:     with(postgres, "DB_QUERY_TIMEOUT", "5"),
+			wantErr: `invalid DB_QUERY_TIMEOUT "5", expected duration like 5s`,
+		},
+		{
+			name:    "negative query timeout",
+			env:     with(postgres, "DB_QUERY_TIMEOUT", "-1s"),
+			wantErr: `invalid DB_QUERY_TIMEOUT "-1s", expected duration like 5s`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range configEnv {
+				t.Setenv(key, tt.env[key])
+			}
+			cfg, err := LoadConfig("")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+// configEnv переменные окружения, которые читает LoadConfig
+var configEnv = []string{
+	"APP_PORT", "STORAGE", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_QUERY_TIMEOUT",
+	"EXCHANGE_RATES_FILE",
+}
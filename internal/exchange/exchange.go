@@ -0,0 +1,52 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// RateProvider источник курсов валют.
+type RateProvider interface {
+	// Rate возвращает стоимость одной единицы валюты from в валюте to.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+var currencyCodeRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidCode проверяет, что code похож на код валюты ISO 4217.
+func ValidCode(code string) bool {
+	return currencyCodeRe.MatchString(code)
+}
+
+// minorUnitsExceptions — валюты, у которых число знаков после запятой отличается от двух.
+var minorUnitsExceptions = map[string]int{
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// MinorUnits возвращает количество знаков после запятой в валюте по ISO 4217.
+func MinorUnits(code string) int {
+	if n, ok := minorUnitsExceptions[code]; ok {
+		return n
+	}
+	return 2
+}
+
+// Convert переводит сумму amount в минимальных единицах валюты from
+// в минимальные единицы валюты to с округлением до ближайшего целого.
+func Convert(ctx context.Context, p RateProvider, amount int, from, to string) (int, error) {
+	if from == to {
+		return amount, nil
+	}
+	rate, err := p.Rate(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("convert %s to %s: %w", from, to, err)
+	}
+	major := float64(amount) / math.Pow10(MinorUnits(from))
+	return int(math.Round(major * rate * math.Pow10(MinorUnits(to)))), nil
+}
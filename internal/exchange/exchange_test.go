@@ -0,0 +1,112 @@
+package exchange
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedRates курсы для тестов: стоимость одной единицы валюты в рублях.
+type fixedRates map[string]float64
+
+func (r fixedRates) Rate(_ context.Context, from, to string) (float64, error) {
+	fromRate, ok := r[from]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	toRate, ok := r[to]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return fromRate / toRate, nil
+}
+
+func TestConvert(t *testing.T) {
+	rates := fixedRates{"RUB": 1, "USD": 80, "JPY": 0.5, "KWD": 260}
+
+	tests := []struct {
+		name     string
+		amount   int
+		from, to string
+		want     int
+		wantErr  bool
+	}{
+		{name: "same currency", amount: 12345, from: "XXX", to: "XXX", want: 12345},
+		{name: "cents to kopecks", amount: 999, from: "USD", to: "RUB", want: 79920},
+		{name: "kopecks to cents rounds", amount: 100, from: "RUB", to: "USD", want: 1},
+		{name: "to currency without minor units", amount: 10000, from: "RUB", to: "JPY", want: 200},
+		{name: "from currency without minor units", amount: 200, from: "JPY", to: "RUB", want: 10000},
+		{name: "three minor digits", amount: 1000, from: "KWD", to: "RUB", want: 26000},
+		{name: "unknown currency", amount: 100, from: "RUB", to: "EUR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(context.Background(), rates, tt.amount, tt.from, tt.to)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnknownCurrency)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidCode(t *testing.T) {
+	assert.True(t, ValidCode("RUB"))
+	assert.False(t, ValidCode("rub"))
+	assert.False(t, ValidCode("RUBL"))
+	assert.False(t, ValidCode(""))
+}
+
+func TestMinorUnits(t *testing.T) {
+	assert.Equal(t, 2, MinorUnits("RUB"))
+	assert.Equal(t, 0, MinorUnits("JPY"))
+	assert.Equal(t, 3, MinorUnits("KWD"))
+}
+
+func TestFileProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: `{"base": "rub", "rates": {"usd": 0.0125, "EUR": 0.01}}`},
+		{name: "invalid json", data: `{`, wantErr: "parse exchange rates"},
+		{name: "invalid base", data: `{"base": "рубль", "rates": {}}`, wantErr: `invalid base currency "рубль"`},
+		{name: "invalid rate", data: `{"base": "RUB", "rates": {"USD": 0}}`, wantErr: `invalid rate for "USD"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rates.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0o600))
+
+			p, err := NewFileProvider(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			rate, err := p.Rate(ctx, "RUB", "USD")
+			require.NoError(t, err)
+			assert.InDelta(t, 0.0125, rate, 1e-12)
+			rate, err = p.Rate(ctx, "USD", "EUR")
+			require.NoError(t, err)
+			assert.InDelta(t, 0.8, rate, 1e-12)
+			_, err = p.Rate(ctx, "RUB", "GBP")
+			assert.ErrorIs(t, err, ErrUnknownCurrency)
+		})
+	}
+
+	_, err := NewFileProvider(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
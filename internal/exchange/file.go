@@ -0,0 +1,64 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileProvider берёт курсы из локального JSON-файла вида
+//
+//	{"base": "RUB", "rates": {"USD": 0.011, "EUR": 0.0102}}
+//
+// где rates — стоимость одной единицы base в указанной валюте.
+type FileProvider struct {
+	base  string
+	rates map[string]float64
+}
+
+type ratesFile struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func NewFileProvider(path string) (*FileProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read exchange rates: %w", err)
+	}
+
+	var f ratesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse exchange rates %s: %w", path, err)
+	}
+
+	base := strings.ToUpper(f.Base)
+	if !ValidCode(base) {
+		return nil, fmt.Errorf("exchange rates %s: invalid base currency %q", path, f.Base)
+	}
+
+	rates := map[string]float64{base: 1}
+	for code, rate := range f.Rates {
+		code = strings.ToUpper(code)
+		if !ValidCode(code) || rate <= 0 {
+			return nil, fmt.Errorf("exchange rates %s: invalid rate for %q", path, code)
+		}
+		rates[code] = rate
+	}
+
+	return &FileProvider{base: base, rates: rates}, nil
+}
+
+func (p *FileProvider) Rate(_ context.Context, from, to string) (float64, error) {
+	fromRate, ok := p.rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, from)
+	}
+	toRate, ok := p.rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, to)
+	}
+	return toRate / fromRate, nil
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"subscriptions/internal/model"
+)
+
+// formulaPrefixes символы, с которых табличные редакторы начинают формулу
+const formulaPrefixes = "=+-@\t\r"
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(sub *model.Subscription) error {
+	return cw.write(record(sub))
+}
+
+// Close дописывает итоги после пустой строки отдельной таблицей со своим заголовком.
+func (cw *csvWriter) Close(summary *Summary) error {
+	if summary != nil {
+		if err := cw.w.Write(nil); err != nil {
+			return err
+		}
+		if err := cw.w.Write(summaryColumns); err != nil {
+			return err
+		}
+		for _, row := range summaryRecords(summary) {
+			if err := cw.write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) write(cells []cell) error {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = c.text
+		if !c.number {
+			row[i] = neutralizeFormula(c.text)
+		}
+	}
+	return cw.w.Write(row)
+}
+
+// neutralizeFormula экранирует текст, который Excel и другие табличные редакторы
+// выполнили бы как формулу: перед ним ставится апостроф, и ячейка остаётся текстом.
+// Импорт снимает этот апостроф через UnquoteFormula, поэтому выгрузку можно загрузить обратно.
+func neutralizeFormula(s string) string {
+	if s != "" && strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// UnquoteFormula снимает апостроф, которым выгрузка экранирует ячейки, похожие на формулы.
+func UnquoteFormula(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
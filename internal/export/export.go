@@ -0,0 +1,164 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"subscriptions/internal/model"
+)
+
+// Format формат выгрузки подписок.
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+	XLSX  Format = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// dateLayout формат месяцев в выгрузке — тот же MM-YYYY, что принимает API
+const dateLayout = "01-2006"
+
+// Writer пишет подписки по одной по мере чтения из хранилища.
+// Close дописывает итоги, если они заданы, и завершает файл.
+type Writer interface {
+	Write(sub *model.Subscription) error
+	Close(summary *Summary) error
+}
+
+// Summary итоги выгрузки: число подписок и сумма списаний за период.
+type Summary struct {
+	Rows     int
+	From, To time.Time
+	Total    *model.Total
+}
+
+// NewWriter создаёт Writer формата format поверх w.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w)
+	case JSONL:
+		return newJSONLWriter(w), nil
+	case XLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// ContentType MIME-тип файла формата format.
+func ContentType(format Format) string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case JSONL:
+		return "application/jsonl; charset=utf-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// columns колонки табличных форматов. Первые совпадают с полями импорта,
+// поэтому выгруженный CSV можно загрузить обратно.
+var columns = []string{
+	"service_name", "price", "currency", "user_id", "start_date", "end_date",
+	"billing_period", "billing_interval", "trial_end_date", "category", "tags", "service_id",
+	"id", "canceled_at", "cancel_reason", "deleted_at", "version",
+}
+
+// cell значение ячейки; числа в XLSX записываются числами, а не текстом.
+type cell struct {
+	text   string
+	number bool
+}
+
+func textCell(s string) cell {
+	return cell{text: s}
+}
+
+func numberCell[T int | int64](n T) cell {
+	return cell{text: strconv.FormatInt(int64(n), 10), number: true}
+}
+
+func monthCell(t *time.Time) cell {
+	if t == nil {
+		return cell{}
+	}
+	return textCell(t.Format(dateLayout))
+}
+
+func timeCell(t *time.Time) cell {
+	if t == nil {
+		return cell{}
+	}
+	return textCell(t.UTC().Format(time.RFC3339))
+}
+
+// record раскладывает подписку по колонкам columns.
+func record(sub *model.Subscription) []cell {
+	serviceID := ""
+	if sub.ServiceID != nil {
+		serviceID = sub.ServiceID.String()
+	}
+	var deletedAt *time.Time
+	if sub.DeletedAt.Valid {
+		deletedAt = &sub.DeletedAt.Time
+	}
+	return []cell{
+		textCell(sub.ServiceName),
+		numberCell(sub.Price),
+		textCell(sub.Currency),
+		textCell(sub.UserID.String()),
+		monthCell(&sub.StartDate),
+		monthCell(sub.EndDate),
+		textCell(string(sub.BillingPeriod)),
+		numberCell(sub.BillingInterval),
+		monthCell(sub.TrialEndDate),
+		textCell(sub.Category),
+		textCell(strings.Join(sub.Tags, ",")),
+		textCell(serviceID),
+		textCell(sub.ID.String()),
+		timeCell(sub.CanceledAt),
+		textCell(string(sub.CancelReason)),
+		timeCell(deletedAt),
+		numberCell(sub.Version),
+	}
+}
+
+// summaryColumns колонки итогов в CSV и XLSX.
+var summaryColumns = []string{"summary", "value", "currency"}
+
+// summaryRecords раскладывает итоги в строки: число подписок, период, суммы по валютам
+// и, если задан, общий итог в одной валюте.
+func summaryRecords(s *Summary) [][]cell {
+	rows := [][]cell{
+		{textCell("subscriptions"), numberCell(s.Rows), {}},
+		{textCell("from"), monthCell(&s.From), {}},
+		{textCell("to"), monthCell(&s.To), {}},
+	}
+	if s.Total == nil {
+		return rows
+	}
+	currencies := make([]string, 0, len(s.Total.ByCurrency))
+	for code := range s.Total.ByCurrency {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+	for _, code := range currencies {
+		rows = append(rows, []cell{textCell("total_by_currency"), numberCell(s.Total.ByCurrency[code]), textCell(code)})
+	}
+	if s.Total.Total != nil && s.Total.Currency != "" {
+		rows = append(rows, []cell{textCell("total"), numberCell(*s.Total.Total), textCell(s.Total.Currency)})
+	}
+	return rows
+}
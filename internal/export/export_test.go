@@ -0,0 +1,187 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+)
+
+func testSubscription(name string) *model.Subscription {
+	return &model.Subscription{
+		ID:              uuid.MustParse("d10b50c1-f1bb-4b7d-99df-f54fe192327d"),
+		ServiceName:     name,
+		Price:           79900,
+		Currency:        "RUB",
+		UserID:          uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		StartDate:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		BillingPeriod:   model.BillingMonth,
+		BillingInterval: 1,
+		Tags:            []string{"video", "family"},
+		Version:         2,
+	}
+}
+
+func testSummary() *Summary {
+	total := 179900
+	return &Summary{
+		Rows: 2,
+		From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		Total: &model.Total{
+			Total:      &total,
+			Currency:   "RUB",
+			ByCurrency: map[string]int{"RUB": 99900, "USD": 1000},
+		},
+	}
+}
+
+// write выгружает подписки в формате format и возвращает содержимое файла.
+func write(t *testing.T, format Format, summary *Summary, subs ...*model.Subscription) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	require.NoError(t, err)
+	for _, sub := range subs {
+		require.NoError(t, w.Write(sub))
+	}
+	require.NoError(t, w.Close(summary))
+	return buf.Bytes()
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", io.Discard)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestNeutralizeFormula(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "Netflix", want: "Netflix"},
+		{in: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{in: "+1", want: "'+1"},
+		{in: "-1", want: "'-1"},
+		{in: "@SUM(A1)", want: "'@SUM(A1)"},
+		{in: "\tcmd", want: "'\tcmd"},
+		{in: "'quoted", want: "'quoted"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := neutralizeFormula(tt.in)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.in, UnquoteFormula(got), "import restores the original text")
+		})
+	}
+}
+
+func TestCSV(t *testing.T) {
+	data := write(t, CSV, testSummary(), testSubscription("Netflix"), testSubscription("=1+1"))
+
+	// Итоги — отдельная таблица с другим числом колонок; пустую строку перед ней csv.Reader пропускает
+	records, err := readCSVLoose(data)
+	require.NoError(t, err)
+
+	require.Len(t, records, 3+1+6)
+	assert.Equal(t, columns, records[0])
+	assert.Equal(t, []string{
+		"Netflix", "79900", "RUB", "11111111-1111-1111-1111-111111111111", "01-2025", "",
+		"month", "1", "", "", "video,family", "",
+		"d10b50c1-f1bb-4b7d-99df-f54fe192327d", "", "", "", "2",
+	}, records[1])
+	assert.Equal(t, "'=1+1", records[2][0])
+
+	assert.Equal(t, summaryColumns, records[3])
+	assert.Equal(t, [][]string{
+		{"subscriptions", "2", ""},
+		{"from", "01-2025", ""},
+		{"to", "12-2025", ""},
+		{"total_by_currency", "99900", "RUB"},
+		{"total_by_currency", "1000", "USD"},
+		{"total", "179900", "RUB"},
+	}, records[4:])
+}
+
+// readCSVLoose читает CSV, строки которого могут иметь разное число колонок.
+func readCSVLoose(data []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+func TestJSONL(t *testing.T) {
+	data := write(t, JSONL, testSummary(), testSubscription("Netflix"))
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var sub model.Subscription
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &sub))
+	assert.Equal(t, "Netflix", sub.ServiceName)
+	assert.Equal(t, []string{"video", "family"}, sub.Tags)
+
+	var summary map[string]map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &summary))
+	assert.Equal(t, float64(2), summary["summary"]["subscriptions"])
+	assert.Equal(t, "01-2025", summary["summary"]["from"])
+	assert.Equal(t, "12-2025", summary["summary"]["to"])
+
+	assert.Len(t, strings.Split(strings.TrimSpace(string(write(t, JSONL, nil, testSubscription("Netflix")))), "\n"), 1)
+}
+
+func TestXLSX(t *testing.T) {
+	tests := []struct {
+		name       string
+		summary    *Summary
+		wantSheets []string
+	}{
+		{name: "without totals", wantSheets: []string{"xl/worksheets/sheet1.xml"}},
+		{name: "with totals", summary: testSummary(), wantSheets: []string{"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := write(t, XLSX, tt.summary, testSubscription("Tom & Jerry <Kids>"))
+			zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			require.NoError(t, err)
+
+			parts := map[string]string{}
+			for _, f := range zr.File {
+				rc, err := f.Open()
+				require.NoError(t, err)
+				body, err := io.ReadAll(rc)
+				require.NoError(t, err)
+				rc.Close()
+				parts[f.Name] = string(body)
+			}
+			for _, name := range append([]string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"}, tt.wantSheets...) {
+				assert.Contains(t, parts, name)
+			}
+			assert.Len(t, parts, 4+len(tt.wantSheets))
+
+			sheet := parts["xl/worksheets/sheet1.xml"]
+			assert.Contains(t, sheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">Tom &amp; Jerry &lt;Kids&gt;</t></is></c>`)
+			assert.Contains(t, sheet, `<c r="B2"><v>79900</v></c>`)
+			assert.NotContains(t, sheet, `r="F2"`, "empty cells are omitted")
+			if tt.summary != nil {
+				assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Totals" sheetId="2" r:id="rId2"/>`)
+				assert.Contains(t, parts["xl/worksheets/sheet2.xml"], `<c r="B7"><v>179900</v></c>`)
+			}
+		})
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, want, columnName(i))
+	}
+}
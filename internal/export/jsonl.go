@@ -0,0 +1,43 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"subscriptions/internal/model"
+)
+
+// jsonlSummary последняя строка выгрузки JSON Lines, если запрошены итоги.
+type jsonlSummary struct {
+	Summary struct {
+		Subscriptions int          `json:"subscriptions"`
+		From          string       `json:"from"`
+		To            string       `json:"to"`
+		Total         *model.Total `json:"total,omitempty"`
+	} `json:"summary"`
+}
+
+// jsonlWriter пишет каждую подписку отдельной строкой в том же JSON, что и API.
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	return &jsonlWriter{enc: json.NewEncoder(w)}
+}
+
+func (jw *jsonlWriter) Write(sub *model.Subscription) error {
+	return jw.enc.Encode(sub)
+}
+
+func (jw *jsonlWriter) Close(summary *Summary) error {
+	if summary == nil {
+		return nil
+	}
+	var line jsonlSummary
+	line.Summary.Subscriptions = summary.Rows
+	line.Summary.From = summary.From.Format(dateLayout)
+	line.Summary.To = summary.To.Format(dateLayout)
+	line.Summary.Total = summary.Total
+	return jw.enc.Encode(line)
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"subscriptions/internal/model"
+)
+
+// Минимальная книга Office Open XML (ECMA-376): строки листа пишутся в архив по мере
+// поступления, текст хранится прямо в ячейках (inlineStr), без общей таблицы строк и стилей.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`%s</Types>`
+	xlsxSheetContentType = `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>%s</sheets></workbook>`
+	xlsxWorkbookSheet = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">%s</Relationships>`
+	xlsxWorkbookRel = `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+type xlsxWriter struct {
+	zw      *zip.Writer
+	created time.Time
+	// sheet текущий лист; в архиве одновременно может писаться только один файл
+	sheet  *bufio.Writer
+	row    int
+	sheets []string
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	xw := &xlsxWriter{zw: zip.NewWriter(w), created: time.Now()}
+	if err := xw.startSheet("Subscriptions"); err != nil {
+		return nil, err
+	}
+	if err := xw.writeRow(textCells(columns)); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) Write(sub *model.Subscription) error {
+	return xw.writeRow(record(sub))
+}
+
+// Close выносит итоги на отдельный лист Totals и дописывает служебные части книги.
+func (xw *xlsxWriter) Close(summary *Summary) error {
+	if err := xw.endSheet(); err != nil {
+		return err
+	}
+	if summary != nil {
+		if err := xw.startSheet("Totals"); err != nil {
+			return err
+		}
+		if err := xw.writeRow(textCells(summaryColumns)); err != nil {
+			return err
+		}
+		for _, row := range summaryRecords(summary) {
+			if err := xw.writeRow(row); err != nil {
+				return err
+			}
+		}
+		if err := xw.endSheet(); err != nil {
+			return err
+		}
+	}
+
+	var overrides, sheets, rels string
+	for i, name := range xw.sheets {
+		n := i + 1
+		overrides += fmt.Sprintf(xlsxSheetContentType, n)
+		sheets += fmt.Sprintf(xlsxWorkbookSheet, name, n, n)
+		rels += fmt.Sprintf(xlsxWorkbookRel, n, n)
+	}
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides)},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, sheets)},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, rels)},
+	}
+	for _, part := range parts {
+		f, err := xw.create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+	return xw.zw.Close()
+}
+
+func (xw *xlsxWriter) create(name string) (io.Writer, error) {
+	return xw.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: xw.created})
+}
+
+func (xw *xlsxWriter) startSheet(name string) error {
+	xw.sheets = append(xw.sheets, name)
+	f, err := xw.create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(xw.sheets)))
+	if err != nil {
+		return err
+	}
+	xw.sheet = bufio.NewWriter(f)
+	xw.row = 0
+	_, err = xw.sheet.WriteString(xlsxSheetStart)
+	return err
+}
+
+func (xw *xlsxWriter) endSheet() error {
+	if _, err := xw.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	return xw.sheet.Flush()
+}
+
+func (xw *xlsxWriter) writeRow(cells []cell) error {
+	xw.row++
+	w := xw.sheet
+	fmt.Fprintf(w, `<row r="%d">`, xw.row)
+	for i, c := range cells {
+		if c.text == "" {
+			continue
+		}
+		ref := columnName(i) + strconv.Itoa(xw.row)
+		if c.number {
+			fmt.Fprintf(w, `<c r="%s"><v>%s</v></c>`, ref, c.text)
+			continue
+		}
+		fmt.Fprintf(w, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		if err := xml.EscapeText(w, []byte(c.text)); err != nil {
+			return err
+		}
+		w.WriteString(`</t></is></c>`)
+	}
+	_, err := w.WriteString(`</row>`)
+	return err
+}
+
+// columnName переводит номер колонки с нуля в буквенное имя: 0 — A, 25 — Z, 26 — AA.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func textCells(texts []string) []cell {
+	cells := make([]cell, len(texts))
+	for i, t := range texts {
+		cells[i] = textCell(t)
+	}
+	return cells
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+	"subscriptions/internal/usecase"
+)
+
+// ActorHeader заголовок с автором изменений для журнала.
+const ActorHeader = "X-Actor"
+
+// withActor передаёт автора изменений из заголовка ActorHeader в контекст запроса.
+func withActor(c *gin.Context) {
+	if actor := strings.TrimSpace(c.GetHeader(ActorHeader)); actor != "" {
+		c.Request = c.Request.WithContext(usecase.WithActor(c.Request.Context(), actor))
+	}
+	c.Next()
+}
+
+// History godoc
+// @Summary Subscription change history
+// @Description Get every recorded change of a subscription in chronological order, also after it has been deleted
+// @Tags audit
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} model.AuditRecord
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/history [get]
+func (h *Handler) History(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	records, err := h.Usecase.SubscriptionHistory(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// ListAudit godoc
+// @Summary Audit log
+// @Description Get subscription changes in chronological order, filtered by subscription, owner, actor, operation and time, with pagination
+// @Tags audit
+// @Produce json
+// @Param subscription_id query string false "Filter by subscription UUID"
+// @Param user_id query string false "Filter by subscription owner UUID"
+// @Param actor query string false "Filter by actor"
+// @Param operation query string false "Filter by operation" Enums(create, update, delete, restore, cancel, purge, price_change, price_change_delete, pause, resume)
+// @Param since query string false "Only changes at or after this time (RFC 3339)"
+// @Param until query string false "Only changes before this time (RFC 3339)"
+// @Param limit query int false "Max number of records to return" default(50)
+// @Param offset query int false "Number of records to skip" default(0)
+// @Success 200 {object} model.AuditList
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /audit [get]
+func (h *Handler) ListAudit(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		badRequest(c, "invalid limit")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		badRequest(c, "invalid offset")
+		return
+	}
+
+	var filter model.AuditFilter
+	if v := c.Query("subscription_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			badRequest(c, "invalid subscription_id")
+			return
+		}
+		filter.SubscriptionID = &id
+	}
+	if v := c.Query("user_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			badRequest(c, "invalid user_id")
+			return
+		}
+		filter.UserID = &id
+	}
+	if actor := c.Query("actor"); actor != "" {
+		filter.Actor = &actor
+	}
+	if v := c.Query("operation"); v != "" {
+		op := model.AuditOperation(v)
+		filter.Operation = &op
+	}
+	if v := c.Query("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			badRequest(c, "invalid since, expected RFC 3339 time")
+			return
+		}
+		filter.Since = &t
+	}
+	if v := c.Query("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			badRequest(c, "invalid until, expected RFC 3339 time")
+			return
+		}
+		filter.Until = &t
+	}
+
+	records, err := h.Usecase.ListAuditRecords(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"subscriptions/internal/model"
+	"subscriptions/internal/usecase"
+)
+
+// BatchItemResult итог одной операции пакета
+// swagger:model
+type BatchItemResult struct {
+	// Index position of the operation in the request
+	Index int           `json:"index"`
+	Op    model.BatchOp `json:"op"`
+	// Status HTTP status the operation would get as a single request
+	Status int `json:"status" example:"201"`
+	// Subscription saved subscription for successful create and update
+	Subscription *model.Subscription `json:"subscription,omitempty"`
+	// Error problem details for a failed operation
+	Error *Problem `json:"error,omitempty"`
+}
+
+// BatchResponse итоги пакетного запроса в порядке операций
+// swagger:model
+type BatchResponse struct {
+	Mode      model.BatchMode   `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// Batch godoc
+// @Summary Create, update and delete subscriptions in one request
+// @Description Apply a list of operations in order. In atomic mode (default) all operations run in one transaction and the first failure rolls back the whole batch and is returned as the error, with the operation index in the detail. In best_effort mode every operation is applied independently and gets its own result
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param batch body model.BatchReq true "Operations"
+// @Success 200 {object} BatchResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/batch [post]
+func (h *Handler) Batch(c *gin.Context) {
+	var req model.BatchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = model.BatchAtomic
+	}
+
+	ops := make([]usecase.BatchOperation, len(req.Operations))
+	for i, opReq := range req.Operations {
+		ops[i] = parseBatchOperation(opReq)
+	}
+
+	results, err := h.Usecase.ApplyBatch(c.Request.Context(), ops, req.Mode)
+	if err != nil {
+		failBatch(c, err)
+		return
+	}
+
+	resp := BatchResponse{Mode: req.Mode, Results: make([]BatchItemResult, len(results))}
+	for i, res := range results {
+		item := BatchItemResult{Index: i, Op: ops[i].Op, Subscription: res.Subscription}
+		if res.Err != nil {
+			status, detail, field := classify(res.Err)
+			item.Status = status
+			item.Error = &Problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(status),
+				Status:   status,
+				Detail:   detail,
+				Instance: c.Request.URL.Path,
+				Field:    field,
+			}
+			resp.Failed++
+		} else {
+			item.Status = batchStatus(ops[i].Op)
+			resp.Succeeded++
+		}
+		resp.Results[i] = item
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// failBatch отвечает ошибкой атомарного пакета со статусом операции, из-за которой он отменён,
+// и её номером в описании.
+func failBatch(c *gin.Context, err error) {
+	var batchErr *usecase.BatchError
+	if !errors.As(err, &batchErr) {
+		fail(c, err)
+		return
+	}
+	status, _, field := classify(batchErr.Err)
+	if status == http.StatusInternalServerError {
+		fail(c, err)
+		return
+	}
+	writeProblem(c, status, err.Error(), field)
+}
+
+// parseBatchOperation разбирает операцию пакета; ошибка разбора сохраняется в самой операции,
+// чтобы в режиме best_effort она стала результатом этой операции, а не всего запроса.
+func parseBatchOperation(req model.BatchOperationReq) usecase.BatchOperation {
+	op := usecase.BatchOperation{Op: req.Op}
+	if req.ID != nil {
+		op.ID = *req.ID
+	}
+	if req.Subscription == nil {
+		return op
+	}
+	if err := binding.Validator.ValidateStruct(req.Subscription); err != nil {
+		op.Err = usecase.NewValidationError("subscription", err.Error())
+		return op
+	}
+	sub, err := parseSubscriptionReq(*req.Subscription)
+	if err != nil {
+		op.Err = err
+		return op
+	}
+	sub.Version = req.Version
+	op.Subscription = sub
+	return op
+}
+
+// batchStatus статус, который получила бы успешная операция отдельным запросом.
+func batchStatus(op model.BatchOp) int {
+	switch op {
+	case model.BatchCreate:
+		return http.StatusCreated
+	case model.BatchDelete:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/usecase"
+)
+
+func TestFailBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+		wantField  string
+	}{
+		{
+			name:       "operation not found",
+			err:        &usecase.BatchError{Index: 2, Err: usecase.ErrSubscriptionNotFound},
+			wantStatus: http.StatusNotFound,
+			wantDetail: "operation 2: subscription not found",
+		},
+		{
+			name:       "operation validation",
+			err:        &usecase.BatchError{Index: 0, Err: usecase.NewValidationError("price", "price must be a positive integer")},
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "operation 0: price must be a positive integer",
+			wantField:  "price",
+		},
+		{
+			name:       "operation internal",
+			err:        &usecase.BatchError{Index: 1, Err: errors.New("connection refused")},
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "internal server error",
+		},
+		{
+			name:       "whole batch",
+			err:        usecase.NewValidationError("mode", "mode must be one of atomic, best_effort"),
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "mode must be one of atomic, best_effort",
+			wantField:  "mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/subscriptions/batch", nil)
+
+			failBatch(c, tt.err)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var problem Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantDetail, problem.Detail)
+			assert.Equal(t, tt.wantField, problem.Field)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func budgetFromReq(req model.BudgetReq) *model.Budget {
+	return &model.Budget{
+		UserID:   req.UserID,
+		Category: req.Category,
+		Amount:   *req.Amount,
+		Currency: req.Currency,
+	}
+}
+
+// CreateBudget godoc
+// @Summary Create a budget
+// @Description Set a monthly spending limit for a user, overall or for one subscription category
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param budget body model.BudgetReq true "Budget request body"
+// @Success 201 {object} model.Budget
+// @Failure 400 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /budgets [post]
+func (h *Handler) CreateBudget(c *gin.Context) {
+	var req model.BudgetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	b := budgetFromReq(req)
+	if err := h.Usecase.CreateBudget(c.Request.Context(), b); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, b)
+}
+
+// ListBudgets godoc
+// @Summary List budgets
+// @Description Get budgets, optionally only those of one user
+// @Tags budgets
+// @Produce json
+// @Param user_id query string false "Filter by user UUID"
+// @Success 200 {array} model.Budget
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /budgets [get]
+func (h *Handler) ListBudgets(c *gin.Context) {
+	var userID *uuid.UUID
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := uuid.Parse(userIDStr)
+		if err != nil {
+			badRequest(c, "invalid user_id")
+			return
+		}
+		userID = &id
+	}
+
+	budgets, err := h.Usecase.ListBudgets(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, budgets)
+}
+
+// GetBudget godoc
+// @Summary Get budget by ID
+// @Tags budgets
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Success 200 {object} model.Budget
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /budgets/{id} [get]
+func (h *Handler) GetBudget(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	b, err := h.Usecase.GetBudget(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// UpdateBudget godoc
+// @Summary Update budget by ID
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Param budget body model.BudgetReq true "Updated budget data"
+// @Success 200 {object} model.Budget
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /budgets/{id} [put]
+func (h *Handler) UpdateBudget(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	var req model.BudgetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	b := budgetFromReq(req)
+	b.ID = id
+	if err := h.Usecase.UpdateBudget(c.Request.Context(), b); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// DeleteBudget godoc
+// @Summary Delete budget by ID
+// @Tags budgets
+// @Param id path string true "Budget ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /budgets/{id} [delete]
+func (h *Handler) DeleteBudget(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	if err := h.Usecase.DeleteBudget(c.Request.Context(), id); err != nil {
+		fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// BudgetStatus godoc
+// @Summary Budget progress
+// @Description Report how much of the budget is used in a month: every charge of the month, including ones not yet made, converted to the budget currency
+// @Tags budgets
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Param month query string false "Month (MM-YYYY), default current month"
+// @Success 200 {object} model.BudgetStatus
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /budgets/{id}/status [get]
+func (h *Handler) BudgetStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	month, ok := parseMonthQuery(c, "month")
+	if !ok {
+		return
+	}
+
+	status, err := h.Usecase.BudgetStatus(c.Request.Context(), id, month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// BudgetStatuses godoc
+// @Summary Progress of all user budgets
+// @Description Report how much of each budget of a user is used in a month
+// @Tags budgets
+// @Produce json
+// @Param user_id query string true "User UUID"
+// @Param month query string false "Month (MM-YYYY), default current month"
+// @Success 200 {array} model.BudgetStatus
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /budgets/status [get]
+func (h *Handler) BudgetStatuses(c *gin.Context) {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		badRequest(c, "user_id is required and must be a UUID")
+		return
+	}
+	month, ok := parseMonthQuery(c, "month")
+	if !ok {
+		return
+	}
+
+	statuses, err := h.Usecase.BudgetStatuses(c.Request.Context(), userID, month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
+// parseMonthQuery разбирает необязательный параметр запроса name в формате MM-YYYY.
+// При ошибке отвечает 400 и возвращает false.
+func parseMonthQuery(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	month, err := parseOptionalMonth(&value)
+	if err != nil {
+		badRequest(c, "invalid "+name+" format, expected MM-YYYY")
+		return nil, false
+	}
+	return month, true
+}
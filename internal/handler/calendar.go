@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/calendar"
+)
+
+// Calendar godoc
+// @Summary Calendar feed of upcoming charges
+// @Description RFC 5545 feed with an all-day recurring event for every active subscription, derived from the start date, billing cycle and end date. Charges skipped by pauses are excluded and scheduled price changes start a new series
+// @Tags calendar
+// @Produce text/calendar
+// @Param user_id path string true "User ID (UUID)"
+// @Param token query string true "Signed token from the calendar link"
+// @Success 200 {file} file
+// @Failure 400 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem "Calendar feed is not configured"
+// @Failure 500 {object} Problem
+// @Router /users/{user_id}/calendar.ics [get]
+func (h *Handler) Calendar(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		badRequest(c, "invalid user_id")
+		return
+	}
+	series, err := h.Usecase.RenewalCalendar(c.Request.Context(), userID, c.Query("token"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+
+	c.Header("Content-Type", calendar.ContentType)
+	c.Header("Content-Disposition", `inline; filename="subscriptions.ics"`)
+	c.Status(http.StatusOK)
+	if err := calendar.Write(c.Writer, series, time.Now()); err != nil {
+		_ = c.Error(err)
+	}
+}
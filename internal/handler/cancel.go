@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+// Cancel godoc
+// @Summary Cancel a subscription
+// @Description Cancel a subscription from the given month or at the end of the current paid period (default). The record is kept for history and analytics
+// @Tags cancellations
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param cancel body model.CancelReq false "Cancellation parameters"
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/cancel [post]
+func (h *Handler) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	var req model.CancelReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		badRequest(c, err.Error())
+		return
+	}
+
+	var effective *time.Time
+	if req.EffectiveDate != "" && req.EffectiveDate != model.CancelEndOfPeriod {
+		t, err := time.Parse(dateLayout, req.EffectiveDate)
+		if err != nil {
+			badRequest(c, "invalid effective_date, expected MM-YYYY or end_of_period")
+			return
+		}
+		effective = &t
+	}
+
+	sub, err := h.Usecase.CancelSubscription(c.Request.Context(), id, effective, req.Reason)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// CancellationStats godoc
+// @Summary Cancellation reasons
+// @Description Count canceled subscriptions per reason code, optionally only those canceled within from..to (MM-YYYY, both months inclusive)
+// @Tags cancellations
+// @Produce json
+// @Param user_id query string false "Filter by user UUID"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
+// @Param from query string false "First month of cancellation (MM-YYYY)"
+// @Param to query string false "Last month of cancellation (MM-YYYY)"
+// @Success 200 {object} model.CancellationStats
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/cancellations [get]
+func (h *Handler) CancellationStats(c *gin.Context) {
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	from, ok := parseMonthQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseMonthQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	stats, err := h.Usecase.CancellationStats(c.Request.Context(), filter, from, to)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func serviceFromReq(req model.ServiceReq) *model.Service {
+	return &model.Service{
+		Name:            req.Name,
+		Aliases:         req.Aliases,
+		DefaultCategory: req.DefaultCategory,
+		URL:             req.URL,
+		LogoURL:         req.LogoURL,
+	}
+}
+
+// CreateService godoc
+// @Summary Add a service to the catalog
+// @Description Create a catalog entry with a canonical name and aliases. New subscriptions whose service_name matches an alias (case- and whitespace-insensitive) are linked to it
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param service body model.ServiceReq true "Service request body"
+// @Success 201 {object} model.Service
+// @Failure 400 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /services [post]
+func (h *Handler) CreateService(c *gin.Context) {
+	var req model.ServiceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	svc := serviceFromReq(req)
+	if err := h.Usecase.CreateService(c.Request.Context(), svc); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, svc)
+}
+
+// ListServices godoc
+// @Summary List the service catalog
+// @Tags services
+// @Produce json
+// @Success 200 {array} model.Service
+// @Failure 500 {object} Problem
+// @Router /services [get]
+func (h *Handler) ListServices(c *gin.Context) {
+	services, err := h.Usecase.ListServices(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, services)
+}
+
+// GetService godoc
+// @Summary Get catalog service by ID
+// @Tags services
+// @Produce json
+// @Param id path string true "Service ID (UUID)"
+// @Success 200 {object} model.Service
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /services/{id} [get]
+func (h *Handler) GetService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	svc, err := h.Usecase.GetService(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, svc)
+}
+
+// UpdateService godoc
+// @Summary Update catalog service by ID
+// @Description Replace the fields and aliases of a catalog entry. Existing subscriptions are not renamed
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path string true "Service ID (UUID)"
+// @Param service body model.ServiceReq true "Updated service data"
+// @Success 200 {object} model.Service
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /services/{id} [put]
+func (h *Handler) UpdateService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	var req model.ServiceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	svc := serviceFromReq(req)
+	svc.ID = id
+	if err := h.Usecase.UpdateService(c.Request.Context(), svc); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, svc)
+}
+
+// DeleteService godoc
+// @Summary Delete catalog service by ID
+// @Description Remove a catalog entry. Linked subscriptions are kept and lose the reference
+// @Tags services
+// @Param id path string true "Service ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /services/{id} [delete]
+func (h *Handler) DeleteService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	if err := h.Usecase.DeleteService(c.Request.Context(), id); err != nil {
+		fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
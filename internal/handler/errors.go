@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"subscriptions/internal/usecase"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem тело ответа с ошибкой в формате RFC 7807
+// swagger:model
+type Problem struct {
+	// Type URI, идентифицирующий тип ошибки
+	Type string `json:"type" example:"about:blank"`
+	// Title краткое описание типа ошибки
+	Title string `json:"title" example:"Bad Request"`
+	// Status HTTP-код ответа
+	Status int `json:"status" example:"400"`
+	// Detail описание конкретной ошибки
+	Detail string `json:"detail,omitempty" example:"invalid UUID"`
+	// Instance путь запроса, на котором возникла ошибка
+	Instance string `json:"instance,omitempty" example:"/subscriptions/123"`
+	// Field поле запроса, не прошедшее проверку
+	Field string `json:"field,omitempty" example:"start_date"`
+}
+
+// writeProblem отправляет ответ в формате problem+json.
+func writeProblem(c *gin.Context, status int, detail string, field string) {
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(status, Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Field:    field,
+	})
+}
+
+// badRequest сообщает об ошибке разбора параметров запроса.
+func badRequest(c *gin.Context, detail string) {
+	writeProblem(c, http.StatusBadRequest, detail, "")
+}
+
+// fail — единая точка преобразования доменных ошибок в HTTP-статус.
+func fail(c *gin.Context, err error) {
+	status, detail, field := classify(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	writeProblem(c, status, detail, field)
+}
+
+// classify возвращает HTTP-статус, описание и поле запроса для доменной ошибки.
+// Подробности внутренних ошибок клиенту не раскрываются.
+func classify(err error) (status int, detail, field string) {
+	var validationErr *usecase.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest, validationErr.Message, validationErr.Field
+	case errors.Is(err, usecase.ErrValidation):
+		return http.StatusBadRequest, err.Error(), ""
+	case errors.Is(err, usecase.ErrNotFound):
+		return http.StatusNotFound, err.Error(), ""
+	case errors.Is(err, usecase.ErrConflict):
+		return http.StatusConflict, err.Error(), ""
+	case errors.Is(err, usecase.ErrForbidden):
+		return http.StatusForbidden, err.Error(), ""
+	case errors.Is(err, usecase.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, err.Error(), ""
+	default:
+		return http.StatusInternalServerError, "internal server error", ""
+	}
+}
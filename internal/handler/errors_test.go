@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/usecase"
+)
+
+func TestFail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+		wantField  string
+	}{
+		{
+			name:       "field validation",
+			err:        usecase.NewValidationError("price", "price must be a positive integer"),
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "price must be a positive integer",
+			wantField:  "price",
+		},
+		{
+			name:       "wrapped validation",
+			err:        fmt.Errorf("%w: bad period", usecase.ErrValidation),
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "validation failed: bad period",
+		},
+		{
+			name:       "not found",
+			err:        usecase.ErrSubscriptionNotFound,
+			wantStatus: http.StatusNotFound,
+			wantDetail: "subscription not found",
+		},
+		{
+			name:       "conflict",
+			err:        fmt.Errorf("%w: duplicate key", usecase.ErrConflict),
+			wantStatus: http.StatusConflict,
+			wantDetail: "conflict: duplicate key",
+		},
+		{
+			name:       "forbidden",
+			err:        usecase.ErrForbidden,
+			wantStatus: http.StatusForbidden,
+			wantDetail: "forbidden",
+		},
+		{
+			// Подробности внутренних ошибок не попадают в ответ
+			name:       "internal",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/subscriptions/1", nil)
+
+			fail(c, tt.err)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+			var problem Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, Problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(tt.wantStatus),
+				Status:   tt.wantStatus,
+				Detail:   tt.wantDetail,
+				Instance: "/subscriptions/1",
+				Field:    tt.wantField,
+			}, problem)
+		})
+	}
+}
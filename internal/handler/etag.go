@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"subscriptions/internal/model"
+	"subscriptions/internal/usecase"
+)
+
+// etag строгий ETag подписки, построенный по её версии.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+func setETag(c *gin.Context, sub *model.Subscription) {
+	c.Header("ETag", etag(sub.Version))
+}
+
+// parseIfMatch возвращает версию подписки из заголовка If-Match; 0 — заголовка нет
+// или он равен "*", и версия не проверяется. If-Match сравнивает ETag строго,
+// поэтому слабый или чужой ETag никогда не совпадает и даёт ErrVersionMismatch.
+func parseIfMatch(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	tag, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, usecase.ErrVersionMismatch
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, usecase.ErrVersionMismatch
+	}
+	return version, nil
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+	"subscriptions/internal/usecase"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion int64
+		wantErr     bool
+	}{
+		{name: "absent"},
+		{name: "any", header: "*"},
+		{name: "strong", header: `"3"`, wantVersion: 3},
+		{name: "weak", header: `W/"3"`, wantErr: true},
+		{name: "unquoted", header: "3", wantErr: true},
+		{name: "not a version", header: `"abc"`, wantErr: true},
+		{name: "zero", header: `"0"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/subscriptions/1", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := parseIfMatch(c)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, usecase.ErrVersionMismatch)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestUpdateIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	New(usecase.New(repository.NewMemoryRepository(), nil, nil, nil)).RegisterRoutes(router)
+
+	body := `{"service_name": "Netflix", "price": 79900, "user_id": "11111111-1111-1111-1111-111111111111", "start_date": "01-2025"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/subscriptions", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	var sub model.Subscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+
+	tests := []struct {
+		name       string
+		ifMatch    string
+		wantStatus int
+		wantETag   string
+	}{
+		{name: "current version", ifMatch: `"1"`, wantStatus: http.StatusOK, wantETag: `"2"`},
+		{name: "stale version", ifMatch: `"1"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "without If-Match", wantStatus: http.StatusOK, wantETag: `"3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/subscriptions/"+sub.ID.String(), strings.NewReader(body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+		})
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/subscriptions/"+sub.ID.String(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+}
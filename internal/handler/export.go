@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"subscriptions/internal/export"
+	"subscriptions/internal/model"
+)
+
+// Export godoc
+// @Summary Export subscriptions
+// @Description Stream every subscription matching the list filters, without pagination, as CSV, JSON Lines or XLSX. With totals=true the file also gets a summary for the from/to period: a second table after an empty line in CSV, a last {"summary": ...} line in JSON Lines, or a Totals sheet in XLSX
+// @Tags subscriptions
+// @Produce text/csv,application/jsonl,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format" Enums(csv, jsonl, xlsx) default(csv)
+// @Param user_id query string false "Filter by user UUID"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
+// @Param active_in query string false "Only subscriptions active and not paused in this month (MM-YYYY)"
+// @Param include_deleted query bool false "Include soft-deleted subscriptions" default(false)
+// @Param totals query bool false "Append a totals summary" default(false)
+// @Param from query string false "Start of the totals period (MM-YYYY), required with totals"
+// @Param to query string false "End of the totals period (MM-YYYY), required with totals"
+// @Param currency query string false "ISO 4217 currency to convert the totals to"
+// @Success 200 {file} file
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/export [get]
+func (h *Handler) Export(c *gin.Context) {
+	format := export.Format(c.DefaultQuery("format", string(export.CSV)))
+	switch format {
+	case export.CSV, export.JSONL, export.XLSX:
+	default:
+		badRequest(c, "format must be one of csv, jsonl, xlsx")
+		return
+	}
+
+	filter, ok := parseListFilter(c)
+	if !ok {
+		return
+	}
+
+	withTotals := false
+	if v := c.Query("totals"); v != "" {
+		var err error
+		if withTotals, err = strconv.ParseBool(v); err != nil {
+			badRequest(c, "invalid totals")
+			return
+		}
+	}
+
+	// Итоги считаются до начала выгрузки: после первых байт ответа об ошибке уже не сообщить
+	var summary *export.Summary
+	if withTotals {
+		from, to, ok := parsePeriod(c)
+		if !ok {
+			return
+		}
+		total, err := h.Usecase.CalculateTotal(c.Request.Context(), filter, from, to, c.Query("currency"))
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		summary = &export.Summary{From: from, To: to, Total: total}
+	}
+
+	filename := fmt.Sprintf("subscriptions-%s.%s", time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	rows := 0
+	w, err := export.NewWriter(format, c.Writer)
+	if err == nil {
+		err = h.Usecase.ExportSubscriptions(c.Request.Context(), filter, func(batch []model.Subscription) error {
+			for i := range batch {
+				if err := w.Write(&batch[i]); err != nil {
+					return err
+				}
+			}
+			rows += len(batch)
+			c.Writer.Flush()
+			return nil
+		})
+	}
+	if err == nil {
+		if summary != nil {
+			summary.Rows = rows
+		}
+		err = w.Close(summary)
+	}
+	if err != nil && !errors.Is(err, c.Request.Context().Err()) {
+		// Заголовки уже отправлены: остаётся оборвать соединение без завершающего чанка,
+		// чтобы клиент не принял неполный файл за целый
+		log.Printf("%s %s: export interrupted after %d rows: %v", c.Request.Method, c.Request.URL.Path, rows, err)
+		if conn, _, err := c.Writer.Hijack(); err == nil {
+			conn.Close()
+		}
+	}
+}
@@ -17,6 +17,8 @@ import (
 	_ "subscriptions/docs"
 )
 
+const dateLayout = "01-2006"
+
 type Handler struct {
 	Usecase *usecase.Usecase
 }
@@ -26,20 +28,220 @@ func New(s *usecase.Usecase) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.Use(withActor)
+
 	sub := r.Group("/subscriptions")
 	{
 		sub.POST("", h.CreateSubscription)
+		sub.POST("/batch", h.Batch)
+		sub.POST("/import", h.Import)
+		sub.POST("/statement", h.AnalyzeStatement)
+		sub.POST("/statement/confirm", h.ConfirmStatement)
 		sub.GET("", h.List)
+		sub.GET("/export", h.Export)
 		sub.GET("/:id", h.Get)
 		sub.PUT("/:id", h.Update)
+		sub.PATCH("/:id", h.Patch)
 		sub.DELETE("/:id", h.Delete)
+		sub.POST("/:id/restore", h.Restore)
+		sub.GET("/:id/history", h.History)
+
+		sub.POST("/:id/prices", h.SchedulePriceChange)
+		sub.GET("/:id/prices", h.ListPriceChanges)
+		sub.DELETE("/:id/prices/:price_id", h.DeletePriceChange)
+
+		sub.POST("/:id/pause", h.Pause)
+		sub.POST("/:id/resume", h.Resume)
+		sub.GET("/:id/pauses", h.ListPauses)
+
+		sub.POST("/:id/cancel", h.Cancel)
 	}
 
 	r.GET("/subscriptions/total", h.Total)
+	r.GET("/subscriptions/total/breakdown", h.Breakdown)
+	r.GET("/subscriptions/trials", h.TrialsEnding)
+	r.GET("/subscriptions/upcoming", h.Upcoming)
+	r.GET("/subscriptions/forecast", h.Forecast)
+	r.GET("/subscriptions/cancellations", h.CancellationStats)
+
+	r.GET("/audit", h.ListAudit)
+
+	services := r.Group("/services")
+	{
+		services.POST("", h.CreateService)
+		services.GET("", h.ListServices)
+		services.GET("/:id", h.GetService)
+		services.PUT("/:id", h.UpdateService)
+		services.DELETE("/:id", h.DeleteService)
+	}
+
+	budgets := r.Group("/budgets")
+	{
+		budgets.POST("", h.CreateBudget)
+		budgets.GET("", h.ListBudgets)
+		budgets.GET("/status", h.BudgetStatuses)
+		budgets.GET("/:id", h.GetBudget)
+		budgets.PUT("/:id", h.UpdateBudget)
+		budgets.DELETE("/:id", h.DeleteBudget)
+		budgets.GET("/:id/status", h.BudgetStatus)
+	}
+
+	users := r.Group("/users/:user_id")
+	{
+		users.GET("/calendar.ics", h.Calendar)
+		users.GET("/insights/duplicates", h.Duplicates)
+	}
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
 
+// parseSubscriptionReq разбирает даты запроса и собирает из него подписку.
+// Бизнес-правила (цена, порядок дат) проверяет usecase.
+func parseSubscriptionReq(req model.SubscriptionReq) (*model.Subscription, error) {
+	startTime, err := time.Parse(dateLayout, req.StartDate)
+	if err != nil {
+		return nil, usecase.NewValidationError("start_date", "invalid start_date format, expected MM-YYYY")
+	}
+
+	var endTime *time.Time
+	if req.EndDate != nil {
+		t, err := time.Parse(dateLayout, *req.EndDate)
+		if err != nil {
+			return nil, usecase.NewValidationError("end_date", "invalid end_date format, expected MM-YYYY")
+		}
+		endTime = &t
+	}
+
+	var trialEnd *time.Time
+	switch {
+	case req.TrialEndDate != nil && req.TrialMonths != 0:
+		return nil, usecase.NewValidationError("trial_months", "only one of trial_end_date and trial_months can be set")
+	case req.TrialEndDate != nil:
+		t, err := time.Parse(dateLayout, *req.TrialEndDate)
+		if err != nil {
+			return nil, usecase.NewValidationError("trial_end_date", "invalid trial_end_date format, expected MM-YYYY")
+		}
+		trialEnd = &t
+	case req.TrialMonths < 0:
+		return nil, usecase.NewValidationError("trial_months", "trial_months must be a positive integer")
+	case req.TrialMonths > 0:
+		t := startTime.AddDate(0, req.TrialMonths, 0)
+		trialEnd = &t
+	}
+
+	return &model.Subscription{
+		ServiceName:     req.ServiceName,
+		Price:           req.Price,
+		UserID:          req.UserID,
+		StartDate:       startTime,
+		EndDate:         endTime,
+		BillingPeriod:   model.BillingPeriod(req.BillingPeriod),
+		BillingInterval: req.BillingInterval,
+		Currency:        req.Currency,
+		TrialEndDate:    trialEnd,
+		Category:        req.Category,
+		Tags:            req.Tags,
+		ServiceID:       req.ServiceID,
+	}, nil
+}
+
+// parseFilter разбирает общие для списка и итогов параметры user_id, service_name,
+// category и tag.
+// При ошибке отвечает 400 и возвращает false.
+func parseFilter(c *gin.Context) (model.SubscriptionFilter, bool) {
+	var filter model.SubscriptionFilter
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := uuid.Parse(userIDStr)
+		if err != nil {
+			badRequest(c, "invalid user_id")
+			return filter, false
+		}
+		filter.UserID = &id
+	}
+
+	if serviceName := c.Query("service_name"); serviceName != "" {
+		filter.ServiceName = &serviceName
+	}
+
+	if serviceIDStr := c.Query("service_id"); serviceIDStr != "" {
+		id, err := uuid.Parse(serviceIDStr)
+		if err != nil {
+			badRequest(c, "invalid service_id")
+			return filter, false
+		}
+		filter.ServiceID = &id
+	}
+
+	if category, ok := c.GetQuery("category"); ok {
+		// Пустое значение выбирает подписки без категории
+		category = model.NormalizeLabel(category)
+		filter.Category = &category
+	}
+
+	if tag := model.NormalizeLabel(c.Query("tag")); tag != "" {
+		filter.Tag = &tag
+	}
+
+	return filter, true
+}
+
+// parseListFilter дополняет фильтр parseFilter параметрами списка active_in и include_deleted.
+// При ошибке отвечает 400 и возвращает false.
+func parseListFilter(c *gin.Context) (model.SubscriptionFilter, bool) {
+	filter, ok := parseFilter(c)
+	if !ok {
+		return filter, false
+	}
+
+	if activeIn := c.Query("active_in"); activeIn != "" {
+		month, err := time.Parse(dateLayout, activeIn)
+		if err != nil {
+			badRequest(c, "invalid active_in format, expected MM-YYYY")
+			return filter, false
+		}
+		filter.ActiveIn = &month
+	}
+
+	if includeDeleted := c.Query("include_deleted"); includeDeleted != "" {
+		var err error
+		filter.IncludeDeleted, err = strconv.ParseBool(includeDeleted)
+		if err != nil {
+			badRequest(c, "invalid include_deleted")
+			return filter, false
+		}
+	}
+
+	return filter, true
+}
+
+// parsePeriod разбирает обязательные параметры from и to в формате MM-YYYY.
+// При ошибке отвечает 400 и возвращает false.
+func parsePeriod(c *gin.Context) (from, to time.Time, ok bool) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	// Парсим from и to — ожидаем формат "01-2006" (MM-YYYY)
+	if fromStr == "" || toStr == "" {
+		badRequest(c, "from and to parameters are required")
+		return from, to, false
+	}
+
+	from, err := time.Parse(dateLayout, fromStr)
+	if err != nil {
+		badRequest(c, "invalid from date format, expected MM-YYYY")
+		return from, to, false
+	}
+
+	to, err = time.Parse(dateLayout, toStr)
+	if err != nil {
+		badRequest(c, "invalid to date format, expected MM-YYYY")
+		return from, to, false
+	}
+
+	return from, to, true
+}
+
 // CreateSubscription godoc
 // @Summary Create a new subscription
 // @Description Create a subscription with service name, price, user ID, start and optional end dates
@@ -47,56 +249,46 @@ func (h *Handler) RegisterRoutes(r *gin.Engine) {
 // @Accept json
 // @Produce json
 // @Param subscription body model.SubscriptionReq true "Subscription request body"
+// @Param allow_duplicate query bool false "Create the subscription even if the user already has a similar one"
 // @Success 201 {object} model.Subscription
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 409 {object} Problem "The subscription looks like a duplicate"
+// @Failure 500 {object} Problem
 // @Router /subscriptions [post]
 func (h *Handler) CreateSubscription(c *gin.Context) {
-	var req model.SubscriptionReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	allowDuplicate := false
+	if v := c.Query("allow_duplicate"); v != "" {
+		var err error
+		if allowDuplicate, err = strconv.ParseBool(v); err != nil {
+			badRequest(c, "invalid allow_duplicate")
+			return
+		}
 	}
 
-	if req.Price < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "price must be a positive integer"})
+	var req model.SubscriptionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
 		return
 	}
 
-	startTime, err := time.Parse("01-2006", req.StartDate)
+	sub, err := parseSubscriptionReq(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
+		fail(c, err)
 		return
 	}
 
-	var endTime *time.Time
-	if req.EndDate != nil {
-		t, err := time.Parse("01-2006", *req.EndDate)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
-			return
-		}
-		if startTime.After(t) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be before or equal to end_date"})
+	if err := h.Usecase.CreateSubscription(c.Request.Context(), sub, allowDuplicate); err != nil {
+		var dupErr *usecase.DuplicateError
+		if errors.As(err, &dupErr) {
+			writeProblem(c, http.StatusConflict, err.Error()+", pass allow_duplicate=true to create it anyway", "")
 			return
 		}
-		endTime = &t
-	}
-
-	sub := &model.Subscription{
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   startTime,
-		EndDate:     endTime,
-	}
-
-	if err := h.Usecase.CreateSubscription(sub); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
 	log.Printf("Created subscription with ID: %s", sub.ID.String())
 
+	setETag(c, sub)
 	c.JSON(http.StatusCreated, sub)
 }
 
@@ -107,24 +299,23 @@ func (h *Handler) CreateSubscription(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Subscription ID (UUID)"
 // @Success 200 {object} model.Subscription
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Header 200 {string} ETag "Subscription version, pass it in If-Match to update"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
 // @Router /subscriptions/{id} [get]
 func (h *Handler) Get(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+		badRequest(c, "invalid UUID")
 		return
 	}
-	sub, err := h.Usecase.GetSubscription(id)
+	sub, err := h.Usecase.GetSubscription(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, usecase.ErrSubscriptionNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		fail(c, err)
 		return
 	}
+	setETag(c, sub)
 	c.JSON(http.StatusOK, sub)
 }
 
@@ -136,123 +327,133 @@ func (h *Handler) Get(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Subscription ID (UUID)"
 // @Param subscription body model.SubscriptionReq true "Updated subscription data"
+// @Param If-Match header string false "ETag from a previous GET; the update is rejected if the subscription has changed since"
 // @Success 200 {object} model.Subscription
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Header 200 {string} ETag "Subscription version"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 500 {object} Problem
 // @Router /subscriptions/{id} [put]
 func (h *Handler) Update(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+		badRequest(c, "invalid UUID")
 		return
 	}
 	var subReq model.SubscriptionReq
 	if err := c.ShouldBindJSON(&subReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		badRequest(c, err.Error())
 		return
 	}
 
-	startDate, err := time.Parse("01-2006", subReq.StartDate)
+	sub, err := parseSubscriptionReq(subReq)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
+		fail(c, err)
 		return
 	}
-
-	var endDatePtr *time.Time
-	if subReq.EndDate != nil {
-		endDate, err := time.Parse("01-2006", *subReq.EndDate)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
-			return
-		}
-		endDatePtr = &endDate
-	}
-
-	sub := model.Subscription{
-		ID:          id,
-		ServiceName: subReq.ServiceName,
-		Price:       subReq.Price,
-		UserID:      subReq.UserID,
-		StartDate:   startDate,
-		EndDate:     endDatePtr,
+	sub.ID = id
+	if sub.Version, err = parseIfMatch(c); err != nil {
+		fail(c, err)
+		return
 	}
 
-	if err := h.Usecase.UpdateSubscription(&sub); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Usecase.UpdateSubscription(c.Request.Context(), sub); err != nil {
+		fail(c, err)
 		return
 	}
+	setETag(c, sub)
 	c.JSON(http.StatusOK, sub)
 }
 
 // Delete godoc
 // @Summary Delete subscription by ID
-// @Description Delete subscription by UUID
+// @Description Soft-delete subscription by UUID. It disappears from lists and forecasts, past charges stay in totals until purged
 // @Tags subscriptions
 // @Param id path string true "Subscription ID (UUID)"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
 // @Router /subscriptions/{id} [delete]
 func (h *Handler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+		badRequest(c, "invalid UUID")
 		return
 	}
-	if err := h.Usecase.DeleteSubscription(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Usecase.DeleteSubscription(c.Request.Context(), id); err != nil {
+		fail(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
+// Restore godoc
+// @Summary Restore a deleted subscription
+// @Description Undo a soft delete that has not been purged yet
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/restore [post]
+func (h *Handler) Restore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	sub, err := h.Usecase.RestoreSubscription(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
 // List godoc
 // @Summary List subscriptions
-// @Description Get all subscriptions optionally filtered by user_id and service_name, with pagination
+// @Description Get all subscriptions optionally filtered by user_id, service_name, category and tag, with pagination
 // @Tags subscriptions
 // @Produce json
 // @Param user_id query string false "Filter by user UUID"
-// @Param service_name query string false "Filter by service name"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
+// @Param active_in query string false "Only subscriptions active and not paused in this month (MM-YYYY)"
+// @Param include_deleted query bool false "Include soft-deleted subscriptions" default(false)
 // @Param limit query int false "Max number of records to return" default(20)
 // @Param offset query int false "Number of records to skip" default(0)
 // @Success 200 {object} map[string]interface{} "Paginated list of subscriptions"
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
 // @Router /subscriptions [get]
 func (h *Handler) List(c *gin.Context) {
-	userIDStr := c.Query("user_id")
-	serviceName := c.Query("service_name")
-
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	if err != nil || limit <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		badRequest(c, "invalid limit")
 		return
 	}
 
 	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	if err != nil || offset < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		badRequest(c, "invalid offset")
 		return
 	}
 
-	var userID *uuid.UUID
-	if userIDStr != "" {
-		id, err := uuid.Parse(userIDStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
-			return
-		}
-		userID = &id
-	}
-
-	var svcName *string
-	if serviceName != "" {
-		svcName = &serviceName
+	filter, ok := parseListFilter(c)
+	if !ok {
+		return
 	}
 
-	result, err := h.Usecase.ListSubscriptions(userID, svcName, limit, offset)
+	result, err := h.Usecase.ListSubscriptions(c.Request.Context(), filter, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
 
@@ -261,67 +462,197 @@ func (h *Handler) List(c *gin.Context) {
 
 // Total godoc
 // @Summary Calculate total subscription cost
-// @Description Calculate total cost for a user and optional service within a date range (from, to in MM-YYYY format)
+// @Description Calculate total cost of charges made for a user and optional service within a date range (from, to in MM-YYYY format, both months inclusive)
 // @Tags subscriptions
 // @Produce json
 // @Param user_id query string true "User UUID"
-// @Param service_name query string false "Service name"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
 // @Param from query string false "Start period (MM-YYYY)"
 // @Param to query string false "End period (MM-YYYY)"
-// @Success 200 {object} map[string]int
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param currency query string false "ISO 4217 currency to convert the total to"
+// @Param group_by query string false "Group totals; category returns an array of model.CategoryTotal" Enums(category)
+// @Success 200 {object} model.Total
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
 // @Router /subscriptions/total [get]
 func (h *Handler) Total(c *gin.Context) {
-	userIDStr := c.Query("user_id")
-	serviceName := c.Query("service_name")
-	fromStr := c.Query("from")
-	toStr := c.Query("to")
+	currency := c.Query("currency")
 
-	var userID *uuid.UUID
-	if userIDStr != "" {
-		id, err := uuid.Parse(userIDStr)
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	switch c.Query("group_by") {
+	case "":
+	case "category":
+		totals, err := h.Usecase.CalculateTotalByCategory(c.Request.Context(), filter, from, to, currency)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+			fail(c, err)
 			return
 		}
-		userID = &id
+		c.JSON(http.StatusOK, totals)
+		return
+	default:
+		badRequest(c, "invalid group_by, expected category")
+		return
+	}
+
+	total, err := h.Usecase.CalculateTotal(c.Request.Context(), filter, from, to, currency)
+	if err != nil {
+		fail(c, err)
+		return
 	}
 
-	var svcName *string
-	if serviceName != "" {
-		svcName = &serviceName
+	c.JSON(http.StatusOK, total)
+}
+
+// Breakdown godoc
+// @Summary Monthly breakdown of totals
+// @Description Split the total cost within a date range (from, to in MM-YYYY format) per month and per service
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "Filter by user UUID"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
+// @Param from query string true "Start period (MM-YYYY)"
+// @Param to query string true "End period (MM-YYYY)"
+// @Param currency query string false "ISO 4217 currency to convert amounts to"
+// @Success 200 {array} model.BreakdownItem
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/total/breakdown [get]
+func (h *Handler) Breakdown(c *gin.Context) {
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
 	}
 
-	// Парсим from и to — ожидаем формат "01-2006" (MM-YYYY)
-	const dateLayout = "01-2006"
-	if fromStr == "" || toStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to parameters are required"})
+	from, to, ok := parsePeriod(c)
+	if !ok {
 		return
 	}
 
-	from, err := time.Parse(dateLayout, fromStr)
+	items, err := h.Usecase.CalculateBreakdown(c.Request.Context(), filter, from, to, c.Query("currency"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date format, expected MM-YYYY"})
+		fail(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, items)
+}
 
-	to, err := time.Parse(dateLayout, toStr)
+// Forecast godoc
+// @Summary Spending forecast
+// @Description Project spend month by month for the next N months, starting with the rest of the current month, from billing cycles, end dates, pauses and scheduled price changes
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "Filter by user UUID"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
+// @Param months query int false "Number of months to forecast" default(12)
+// @Param currency query string false "ISO 4217 currency to convert amounts to"
+// @Success 200 {array} model.ForecastMonth
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/forecast [get]
+func (h *Handler) Forecast(c *gin.Context) {
+	months, err := strconv.Atoi(c.DefaultQuery("months", "12"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date format, expected MM-YYYY"})
+		badRequest(c, "invalid months")
 		return
 	}
 
-	if from.After(to) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before or equal to to"})
+	filter, ok := parseFilter(c)
+	if !ok {
 		return
 	}
 
-	sum, err := h.Usecase.CalculateTotal(userID, svcName, from, to)
+	forecast, err := h.Usecase.Forecast(c.Request.Context(), filter, months, c.Query("currency"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, forecast)
+}
 
-	c.JSON(http.StatusOK, gin.H{"total": sum})
+// TrialsEnding godoc
+// @Summary List trials ending soon
+// @Description Get subscriptions whose free trial ends within the next N days, followed by a paid charge
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "Filter by user UUID"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
+// @Param days query int false "Look-ahead window in days" default(7)
+// @Success 200 {array} model.Subscription
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/trials [get]
+func (h *Handler) TrialsEnding(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil {
+		badRequest(c, "invalid days")
+		return
+	}
+
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	subs, err := h.Usecase.ListTrialsEnding(c.Request.Context(), filter, days)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+// Upcoming godoc
+// @Summary List upcoming charges
+// @Description Get every charge expected in the next N days, derived from start date, billing cycle, end date, trials, pauses and price changes
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "Filter by user UUID"
+// @Param service_name query string false "Filter by service name, case- and whitespace-insensitive; catalog aliases select the whole catalog entry"
+// @Param service_id query string false "Filter by service catalog entry (UUID)"
+// @Param category query string false "Filter by category, empty value selects subscriptions without a category"
+// @Param tag query string false "Filter by tag"
+// @Param days query int false "Look-ahead window in days" default(30)
+// @Success 200 {array} model.Charge
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/upcoming [get]
+func (h *Handler) Upcoming(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		badRequest(c, "invalid days")
+		return
+	}
+
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	charges, err := h.Usecase.UpcomingCharges(c.Request.Context(), filter, days)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, charges)
 }
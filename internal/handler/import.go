@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"subscriptions/internal/export"
+	"subscriptions/internal/model"
+	"subscriptions/internal/usecase"
+)
+
+// maxImportSize ограничивает размер загружаемого файла
+const maxImportSize = 10 << 20
+
+// importFields поля SubscriptionReq, которые можно взять из колонок CSV.
+var importFields = []string{
+	"service_name", "price", "currency", "user_id", "start_date", "end_date",
+	"billing_period", "billing_interval", "trial_end_date", "trial_months",
+	"category", "tags", "service_id",
+}
+
+// Import godoc
+// @Summary Import subscriptions from CSV
+// @Description Import subscriptions from a CSV file with a header row. Every row is validated like POST /subscriptions; if any row fails, nothing is saved and the row errors are returned with status 422. With dry_run=true the file is only validated. Columns are matched to fields by name unless mapping is given; tags are comma-separated within a cell
+// @Tags subscriptions
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file"
+// @Param mapping formData string false "JSON object mapping subscription fields to CSV column names, e.g. {\"service_name\":\"Service\",\"price\":\"Amount\"}"
+// @Param delimiter formData string false "Column delimiter" default(,)
+// @Param user_id formData string false "Owner for rows without a user_id column"
+// @Param dry_run query bool false "Validate only, do not save"
+// @Success 200 {object} model.ImportResult
+// @Success 201 {object} model.ImportResult
+// @Failure 400 {object} Problem
+// @Failure 422 {object} model.ImportResult
+// @Failure 500 {object} Problem
+// @Router /subscriptions/import [post]
+func (h *Handler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			badRequest(c, "invalid dry_run")
+			return
+		}
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		badRequest(c, "file is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	defer file.Close()
+
+	delimiter := ','
+	if v := c.PostForm("delimiter"); v != "" {
+		r, size := utf8.DecodeRuneInString(v)
+		if size != len(v) || r == '"' || r == '\r' || r == '\n' {
+			badRequest(c, "delimiter must be a single character")
+			return
+		}
+		delimiter = r
+	}
+
+	var defaultUser uuid.UUID
+	if v := c.PostForm("user_id"); v != "" {
+		if defaultUser, err = uuid.Parse(v); err != nil {
+			badRequest(c, "invalid user_id")
+			return
+		}
+	}
+
+	mapping := make(map[string]string)
+	if v := c.PostForm("mapping"); v != "" {
+		if err := json.Unmarshal([]byte(v), &mapping); err != nil {
+			badRequest(c, "mapping must be a JSON object of field names to column names")
+			return
+		}
+		for field := range mapping {
+			if !slices.Contains(importFields, field) {
+				badRequest(c, fmt.Sprintf("unknown field %q in mapping", field))
+				return
+			}
+		}
+	}
+
+	rows, err := readImportCSV(file, delimiter, mapping, defaultUser)
+	if err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	res, err := h.Usecase.ImportSubscriptions(c.Request.Context(), rows, dryRun)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	switch {
+	case len(res.Errors) > 0:
+		c.JSON(http.StatusUnprocessableEntity, res)
+	case dryRun:
+		c.JSON(http.StatusOK, res)
+	default:
+		c.JSON(http.StatusCreated, res)
+	}
+}
+
+// readImportCSV читает CSV с заголовком и разбирает строки в подписки. Колонки ищутся
+// по mapping, а для неуказанных в нём полей — по имени поля, без учёта регистра.
+// Ошибка возвращается, только если файл нельзя разобрать целиком.
+func readImportCSV(r io.Reader, delimiter rune, mapping map[string]string, defaultUser uuid.UUID) ([]usecase.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	// Excel сохраняет CSV в UTF-8 с BOM
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		byName[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	columns := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		i, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		switch {
+		case ok:
+			columns[field] = i
+		case mapped:
+			return nil, fmt.Errorf("column %q mapped to %s not found", name, field)
+		}
+	}
+
+	var rows []usecase.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := usecase.ImportRow{Line: line}
+		row.Subscription, row.Err = parseImportRecord(record, columns, defaultUser)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseImportRecord собирает SubscriptionReq из строки CSV и проверяет её так же, как тело POST /subscriptions.
+func parseImportRecord(record []string, columns map[string]int, defaultUser uuid.UUID) (*model.Subscription, error) {
+	cell := func(field string) string {
+		if i, ok := columns[field]; ok {
+			return export.UnquoteFormula(strings.TrimSpace(record[i]))
+		}
+		return ""
+	}
+	optional := func(field string) *string {
+		if v := cell(field); v != "" {
+			return &v
+		}
+		return nil
+	}
+	integer := func(field string) (int, error) {
+		v := cell(field)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, usecase.NewValidationError(field, field+" must be an integer")
+		}
+		return n, nil
+	}
+
+	req := model.SubscriptionReq{
+		ServiceName:   cell("service_name"),
+		Currency:      cell("currency"),
+		UserID:        defaultUser,
+		StartDate:     cell("start_date"),
+		EndDate:       optional("end_date"),
+		BillingPeriod: cell("billing_period"),
+		TrialEndDate:  optional("trial_end_date"),
+		Category:      cell("category"),
+	}
+	var err error
+	if req.Price, err = integer("price"); err != nil {
+		return nil, err
+	}
+	if req.BillingInterval, err = integer("billing_interval"); err != nil {
+		return nil, err
+	}
+	if req.TrialMonths, err = integer("trial_months"); err != nil {
+		return nil, err
+	}
+	if v := cell("user_id"); v != "" {
+		if req.UserID, err = uuid.Parse(v); err != nil {
+			return nil, usecase.NewValidationError("user_id", "invalid user_id")
+		}
+	}
+	if v := cell("service_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, usecase.NewValidationError("service_id", "invalid service_id")
+		}
+		req.ServiceID = &id
+	}
+	if v := cell("tags"); v != "" {
+		req.Tags = strings.Split(v, ",")
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, requestFieldError(&req, err)
+	}
+	return parseSubscriptionReq(req)
+}
+
+// requestFieldError переводит ошибку проверки тегов binding в ValidationError
+// с JSON-именем первого неверного поля.
+func requestFieldError(req any, err error) error {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) == 0 {
+		return usecase.NewValidationError("", err.Error())
+	}
+	fe := fieldErrs[0]
+	name := fe.Field()
+	if f, ok := reflect.TypeOf(req).Elem().FieldByName(fe.StructField()); ok {
+		name, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+	}
+	if fe.Tag() == "required" {
+		return usecase.NewValidationError(name, name+" is required")
+	}
+	return usecase.NewValidationError(name, fmt.Sprintf("%s failed %s validation", name, fe.Tag()))
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/usecase"
+)
+
+func TestReadImportCSV(t *testing.T) {
+	owner := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	type wantRow struct {
+		line      int
+		service   string
+		price     int
+		wantField string
+	}
+	tests := []struct {
+		name      string
+		csv       string
+		delimiter rune
+		mapping   map[string]string
+		want      []wantRow
+		wantErr   string
+	}{
+		{
+			name: "columns by field name",
+			csv: "service_name,price,user_id,start_date,tags\n" +
+				"Netflix,79900,11111111-1111-1111-1111-111111111111,01-2025,\"video,family\"\n",
+			want: []wantRow{{line: 2, service: "Netflix", price: 79900}},
+		},
+		{
+			name:      "mapping, delimiter and BOM",
+			csv:       "\ufeffService;Amount;Start\nSpotify;16900;03-2025\n",
+			delimiter: ';',
+			mapping:   map[string]string{"service_name": "Service", "price": "amount", "start_date": "Start"},
+			want:      []wantRow{{line: 2, service: "Spotify", price: 16900}},
+		},
+		{
+			name: "row errors",
+			csv: "service_name,price,start_date\n" +
+				"Netflix,abc,01-2025\n" +
+				"Spotify,16900,2025-03\n" +
+				",100,01-2025\n",
+			want: []wantRow{
+				{line: 2, wantField: "price"},
+				{line: 3, wantField: "start_date"},
+				{line: 4, wantField: "service_name"},
+			},
+		},
+		{
+			// Выгрузка экранирует ячейки, похожие на формулы, а импорт снимает апостроф
+			name: "exported formula cell",
+			csv:  "service_name,price,start_date\n'=Netflix,79900,01-2025\n",
+			want: []wantRow{{line: 2, service: "=Netflix", price: 79900}},
+		},
+		{name: "empty", csv: "", wantErr: "file is empty"},
+		{name: "mapped column missing", csv: "name,price\n", mapping: map[string]string{"service_name": "Service"}, wantErr: `column "Service" mapped to service_name not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delimiter := tt.delimiter
+			if delimiter == 0 {
+				delimiter = ','
+			}
+			rows, err := readImportCSV(strings.NewReader(tt.csv), delimiter, tt.mapping, owner)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rows, len(tt.want))
+			for i, want := range tt.want {
+				row := rows[i]
+				assert.Equal(t, want.line, row.Line)
+				if want.wantField != "" {
+					var validationErr *usecase.ValidationError
+					require.True(t, errors.As(row.Err, &validationErr), "got %v", row.Err)
+					assert.Equal(t, want.wantField, validationErr.Field)
+					continue
+				}
+				require.NoError(t, row.Err)
+				assert.Equal(t, want.service, row.Subscription.ServiceName)
+				assert.Equal(t, want.price, row.Subscription.Price)
+				assert.Equal(t, owner, row.Subscription.UserID)
+			}
+		})
+	}
+}
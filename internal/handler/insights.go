@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+// Duplicates godoc
+// @Summary Likely duplicate subscriptions
+// @Description Find pairs of the user's subscriptions that are active at the same time and either belong to the same service (same name ignoring case and spaces, or the same catalog entry) or have near-identical names and prices
+// @Tags insights
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {array} model.Duplicate
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /users/{user_id}/insights/duplicates [get]
+func (h *Handler) Duplicates(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		badRequest(c, "invalid user_id")
+		return
+	}
+	var duplicates []model.Duplicate
+	if duplicates, err = h.Usecase.FindDuplicates(c.Request.Context(), userID); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, duplicates)
+}
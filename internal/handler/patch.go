@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// Patch godoc
+// @Summary Partially update subscription
+// @Description Apply an RFC 7396 JSON merge patch to the subscription fields of SubscriptionReq. Omitted fields keep their values, null removes a field (e.g. end_date), arrays such as tags are replaced as a whole. The merged result is validated like a new subscription
+// @Tags subscriptions
+// @Accept json,application/merge-patch+json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param patch body model.SubscriptionReq true "Merge patch with the fields to change"
+// @Param If-Match header string false "ETag from a previous GET; the update is rejected if the subscription has changed since"
+// @Success 200 {object} model.Subscription
+// @Header 200 {string} ETag "Subscription version"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 415 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id} [patch]
+func (h *Handler) Patch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	if ct := c.ContentType(); ct != mergePatchContentType && ct != binding.MIMEJSON {
+		writeProblem(c, http.StatusUnsupportedMediaType, "expected "+mergePatchContentType+" body", "")
+		return
+	}
+
+	var patch map[string]any
+	dec := json.NewDecoder(c.Request.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&patch); err != nil || patch == nil {
+		badRequest(c, "merge patch must be a JSON object")
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+
+	current, err := h.Usecase.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+
+	subReq, err := applyMergePatch(current, patch)
+	if err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&subReq); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	sub, err := parseSubscriptionReq(subReq)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	sub.ID = id
+	// Патч построен по прочитанной версии: если подписку успели изменить, вернётся 412
+	sub.Version = current.Version
+	if version != 0 {
+		sub.Version = version
+	}
+
+	if err := h.Usecase.UpdateSubscription(c.Request.Context(), sub); err != nil {
+		fail(c, err)
+		return
+	}
+	setETag(c, sub)
+	c.JSON(http.StatusOK, sub)
+}
+
+// applyMergePatch накладывает патч на подписку в виде SubscriptionReq и разбирает результат.
+// Неизвестные поля считаются ошибкой, чтобы опечатка в имени поля не терялась молча.
+func applyMergePatch(sub *model.Subscription, patch map[string]any) (model.SubscriptionReq, error) {
+	doc, err := subscriptionDoc(sub)
+	if err != nil {
+		return model.SubscriptionReq{}, err
+	}
+
+	// Сохранённая дата окончания пробного периода конфликтовала бы с новой длиной
+	if _, ok := patch["trial_months"]; ok {
+		if _, ok := patch["trial_end_date"]; !ok {
+			delete(doc, "trial_end_date")
+		}
+	}
+	// Новое название сверяется с каталогом заново, если сервис не указан явно
+	if _, ok := patch["service_name"]; ok {
+		if _, ok := patch["service_id"]; !ok {
+			delete(doc, "service_id")
+		}
+	}
+
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return model.SubscriptionReq{}, err
+	}
+	var req model.SubscriptionReq
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return model.SubscriptionReq{}, err
+	}
+	return req, nil
+}
+
+// subscriptionDoc представляет подписку тем же JSON-документом, что принимает PUT.
+func subscriptionDoc(sub *model.Subscription) (map[string]any, error) {
+	req := model.SubscriptionReq{
+		ServiceName:     sub.ServiceName,
+		Price:           sub.Price,
+		Currency:        sub.Currency,
+		UserID:          sub.UserID,
+		StartDate:       sub.StartDate.Format(dateLayout),
+		BillingPeriod:   string(sub.BillingPeriod),
+		BillingInterval: sub.BillingInterval,
+		Category:        sub.Category,
+		Tags:            sub.Tags,
+		ServiceID:       sub.ServiceID,
+	}
+	if sub.EndDate != nil {
+		end := sub.EndDate.Format(dateLayout)
+		req.EndDate = &end
+	}
+	if sub.TrialEndDate != nil {
+		trialEnd := sub.TrialEndDate.Format(dateLayout)
+		req.TrialEndDate = &trialEnd
+	}
+
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// mergePatch применяет patch к target по RFC 7396: null удаляет поле,
+// объекты сливаются рекурсивно, остальные значения заменяются целиком.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = mergePatch(t[key], value)
+	}
+	return t
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+)
+
+func decodeJSON(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+// TestMergePatch проверяет примеры из приложения A RFC 7396.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.patch, func(t *testing.T) {
+			got := mergePatch(decodeJSON(t, tt.target), decodeJSON(t, tt.patch))
+			assert.Equal(t, decodeJSON(t, tt.want), got)
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	serviceID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	trialEnd := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	sub := &model.Subscription{
+		ServiceName:     "Netflix",
+		Price:           39900,
+		Currency:        "RUB",
+		UserID:          uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		StartDate:       start,
+		EndDate:         &end,
+		TrialEndDate:    &trialEnd,
+		BillingPeriod:   model.BillingMonth,
+		BillingInterval: 1,
+		Category:        "entertainment",
+		Tags:            []string{"family", "work"},
+		ServiceID:       &serviceID,
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		check   func(t *testing.T, req model.SubscriptionReq)
+		wantErr bool
+	}{
+		{
+			name:  "empty patch keeps fields",
+			patch: `{}`,
+			check: func(t *testing.T, req model.SubscriptionReq) {
+				assert.Equal(t, "Netflix", req.ServiceName)
+				assert.Equal(t, 39900, req.Price)
+				assert.Equal(t, "07-2025", req.StartDate)
+				assert.Equal(t, ptr("06-2026"), req.EndDate)
+				assert.Equal(t, ptr("09-2025"), req.TrialEndDate)
+				assert.Equal(t, []string{"family", "work"}, req.Tags)
+				assert.Equal(t, &serviceID, req.ServiceID)
+			},
+		},
+		{
+			name:  "changes price",
+			patch: `{"price":49900}`,
+			check: func(t *testing.T, req model.SubscriptionReq) {
+				assert.Equal(t, 49900, req.Price)
+				assert.Equal(t, "Netflix", req.ServiceName)
+			},
+		},
+		{
+			name:  "null removes end date",
+			patch: `{"end_date":null}`,
+			check: func(t *testing.T, req model.SubscriptionReq) {
+				assert.Nil(t, req.EndDate)
+				assert.Equal(t, ptr("09-2025"), req.TrialEndDate)
+			},
+		},
+		{
+			name:  "tags are replaced",
+			patch: `{"tags":["personal"]}`,
+			check: func(t *testing.T, req model.SubscriptionReq) {
+				assert.Equal(t, []string{"personal"}, req.Tags)
+			},
+		},
+		{
+			name:  "trial months replace trial end date",
+			patch: `{"trial_months":2}`,
+			check: func(t *testing.T, req model.SubscriptionReq) {
+				assert.Nil(t, req.TrialEndDate)
+				assert.Equal(t, 2, req.TrialMonths)
+			},
+		},
+		{
+			name:  "new service name drops service link",
+			patch: `{"service_name":"Кинопоиск"}`,
+			check: func(t *testing.T, req model.SubscriptionReq) {
+				assert.Equal(t, "Кинопоиск", req.ServiceName)
+				assert.Nil(t, req.ServiceID)
+			},
+		},
+		{
+			name:  "explicit service id is kept",
+			patch: `{"service_name":"Netflix Premium","service_id":"33333333-3333-3333-3333-333333333333"}`,
+			check: func(t *testing.T, req model.SubscriptionReq) {
+				assert.Equal(t, &serviceID, req.ServiceID)
+			},
+		},
+		{
+			name:    "unknown field",
+			patch:   `{"prise":49900}`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			patch:   `{"price":"free"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, ok := decodeJSON(t, tt.patch).(map[string]any)
+			require.True(t, ok)
+
+			req, err := applyMergePatch(sub, patch)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, req)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+// parseOptionalMonth разбирает необязательный месяц в формате MM-YYYY.
+func parseOptionalMonth(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(dateLayout, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Pause godoc
+// @Summary Pause a subscription
+// @Description Pause a subscription from the given month (default current month). Paused months are not charged
+// @Tags pauses
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param pause body model.PauseReq false "Pause parameters"
+// @Success 201 {object} model.Pause
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/pause [post]
+func (h *Handler) Pause(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	var req model.PauseReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		badRequest(c, err.Error())
+		return
+	}
+	from, err := parseOptionalMonth(req.From)
+	if err != nil {
+		badRequest(c, "invalid from format, expected MM-YYYY")
+		return
+	}
+
+	pause, err := h.Usecase.PauseSubscription(c.Request.Context(), id, from)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, pause)
+}
+
+// Resume godoc
+// @Summary Resume a paused subscription
+// @Description Close the open pause of a subscription. Charges resume from the given month
+// @Tags pauses
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param resume body model.ResumeReq false "Resume parameters"
+// @Success 200 {object} model.Pause
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/resume [post]
+func (h *Handler) Resume(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	var req model.ResumeReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		badRequest(c, err.Error())
+		return
+	}
+	at, err := parseOptionalMonth(req.At)
+	if err != nil {
+		badRequest(c, "invalid at format, expected MM-YYYY")
+		return
+	}
+
+	pause, err := h.Usecase.ResumeSubscription(c.Request.Context(), id, at)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pause)
+}
+
+// ListPauses godoc
+// @Summary List pauses
+// @Description Get the pause history of a subscription ordered by start month
+// @Tags pauses
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} model.Pause
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/pauses [get]
+func (h *Handler) ListPauses(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	pauses, err := h.Usecase.ListPauses(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pauses)
+}
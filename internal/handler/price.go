@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+// SchedulePriceChange godoc
+// @Summary Schedule a price change
+// @Description Set a new subscription price effective from the given month. Charges before that month keep the previous price
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param price body model.PriceChangeReq true "Price change"
+// @Success 201 {object} model.PriceChange
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/prices [post]
+func (h *Handler) SchedulePriceChange(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	var req model.PriceChangeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	effectiveFrom, err := time.Parse(dateLayout, req.EffectiveFrom)
+	if err != nil {
+		badRequest(c, "invalid effective_from format, expected MM-YYYY")
+		return
+	}
+
+	pc := &model.PriceChange{
+		SubscriptionID: id,
+		EffectiveFrom:  effectiveFrom,
+		Price:          *req.Price,
+	}
+	if err := h.Usecase.SchedulePriceChange(c.Request.Context(), pc); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, pc)
+}
+
+// ListPriceChanges godoc
+// @Summary List price changes
+// @Description Get the price history of a subscription ordered by effective date
+// @Tags prices
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} model.PriceChange
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/prices [get]
+func (h *Handler) ListPriceChanges(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	changes, err := h.Usecase.ListPriceChanges(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+// DeletePriceChange godoc
+// @Summary Delete a price change
+// @Description Remove a scheduled or recorded price change
+// @Tags prices
+// @Param id path string true "Subscription ID (UUID)"
+// @Param price_id path string true "Price change ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/{id}/prices/{price_id} [delete]
+func (h *Handler) DeletePriceChange(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid UUID")
+		return
+	}
+	priceID, err := uuid.Parse(c.Param("price_id"))
+	if err != nil {
+		badRequest(c, "invalid price_id")
+		return
+	}
+	if err := h.Usecase.DeletePriceChange(c.Request.Context(), id, priceID); err != nil {
+		fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
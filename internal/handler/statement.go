@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"subscriptions/internal/exchange"
+	"subscriptions/internal/model"
+	"subscriptions/internal/statement"
+	"subscriptions/internal/usecase"
+)
+
+// statementColumns колонки CSV-выписки, которые можно указать в mapping
+var statementColumns = []string{"date", "amount", "payee", "currency"}
+
+// dateFormatTokens переводит формат даты вида DD.MM.YYYY в нотацию Go
+var dateFormatTokens = strings.NewReplacer(
+	"YYYY", "2006", "YY", "06", "MM", "01", "DD", "02", "HH", "15", "mm", "04", "ss", "05",
+)
+
+// AnalyzeStatement godoc
+// @Summary Find subscriptions in a bank statement
+// @Description Upload an OFX or CSV bank statement and get recurring charges that look like subscriptions: the same merchant, a roughly constant amount and a weekly, monthly, quarterly or yearly interval. Each candidate holds a subscription request with the catalog service name where the merchant matches one. Nothing is saved; send the chosen candidates to /subscriptions/statement/confirm. A CSV statement needs a header with date, amount (charges negative) and payee columns
+// @Tags subscriptions
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "OFX or CSV statement"
+// @Param user_id formData string true "Owner of the proposed subscriptions"
+// @Param format formData string false "Statement format, detected from the content when omitted" Enums(ofx, csv)
+// @Param currency formData string false "Currency of transactions without one in the statement (default RUB)"
+// @Param delimiter formData string false "CSV column delimiter" default(,)
+// @Param date_format formData string false "CSV date format, e.g. DD/MM/YYYY; common formats are detected when omitted"
+// @Param mapping formData string false "JSON object mapping date, amount, payee and currency to CSV column names, e.g. {\"payee\":\"Merchant\"}"
+// @Success 200 {object} model.StatementAnalysis
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/statement [post]
+func (h *Handler) AnalyzeStatement(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		badRequest(c, "file is required")
+		return
+	}
+	userID, err := uuid.Parse(c.PostForm("user_id"))
+	if err != nil {
+		badRequest(c, "invalid user_id")
+		return
+	}
+	currency := strings.ToUpper(strings.TrimSpace(c.PostForm("currency")))
+	if currency != "" && !exchange.ValidCode(currency) {
+		badRequest(c, "currency must be an ISO 4217 code")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+
+	format := statement.Format(strings.ToLower(c.PostForm("format")))
+	if format == "" {
+		format = statement.DetectFormat(data)
+	}
+
+	var txs []statement.Transaction
+	switch format {
+	case statement.OFX:
+		txs, err = statement.ParseOFX(bytes.NewReader(data), currency)
+	case statement.CSV:
+		opts, optsErr := statementCSVOptions(c, currency)
+		if optsErr != nil {
+			badRequest(c, optsErr.Error())
+			return
+		}
+		txs, err = statement.ParseCSV(bytes.NewReader(data), opts)
+	default:
+		badRequest(c, "format must be one of ofx, csv")
+		return
+	}
+	if err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	res, err := h.Usecase.AnalyzeStatement(c.Request.Context(), userID, txs)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// statementCSVOptions собирает настройки разбора CSV-выписки из полей формы.
+func statementCSVOptions(c *gin.Context, currency string) (statement.CSVOptions, error) {
+	opts := statement.CSVOptions{Currency: currency}
+	if v := c.PostForm("delimiter"); v != "" {
+		r, size := utf8.DecodeRuneInString(v)
+		if size != len(v) || r == '"' || r == '\r' || r == '\n' {
+			return opts, errors.New("delimiter must be a single character")
+		}
+		opts.Delimiter = r
+	}
+	if v := c.PostForm("date_format"); v != "" {
+		opts.DateLayout = dateFormatTokens.Replace(v)
+	}
+
+	mapping := make(map[string]string)
+	if v := c.PostForm("mapping"); v != "" {
+		if err := json.Unmarshal([]byte(v), &mapping); err != nil {
+			return opts, errors.New("mapping must be a JSON object of field names to column names")
+		}
+		for field := range mapping {
+			if !slices.Contains(statementColumns, field) {
+				return opts, fmt.Errorf("unknown field %q in mapping", field)
+			}
+		}
+	}
+	opts.DateColumn = mapping["date"]
+	opts.AmountColumn = mapping["amount"]
+	opts.PayeeColumn = mapping["payee"]
+	opts.CurrencyColumn = mapping["currency"]
+	return opts, nil
+}
+
+// ConfirmStatement godoc
+// @Summary Create subscriptions from statement candidates
+// @Description Create the subscriptions chosen from /subscriptions/statement candidates, possibly edited, with the same rules as POST /subscriptions, except that duplicates are not checked: candidates similar to recorded subscriptions already carry existing_subscription_id. All of them are created in one transaction: if one fails, none are saved and the error detail names its index
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param candidates body model.ConfirmCandidatesReq true "Chosen subscriptions"
+// @Success 201 {array} model.Subscription
+// @Failure 400 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /subscriptions/statement/confirm [post]
+func (h *Handler) ConfirmStatement(c *gin.Context) {
+	var req model.ConfirmCandidatesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err.Error())
+		return
+	}
+
+	ops := make([]usecase.BatchOperation, len(req.Subscriptions))
+	for i := range req.Subscriptions {
+		ops[i] = parseBatchOperation(model.BatchOperationReq{Op: model.BatchCreate, Subscription: &req.Subscriptions[i]})
+	}
+	// Пакет не проверяет дубликаты: похожие кандидаты уже помечены existing_subscription_id
+	results, err := h.Usecase.ApplyBatch(c.Request.Context(), ops, model.BatchAtomic)
+	if err != nil {
+		failBatch(c, err)
+		return
+	}
+
+	subs := make([]*model.Subscription, len(results))
+	for i, res := range results {
+		subs[i] = res.Subscription
+	}
+	c.JSON(http.StatusCreated, subs)
+}
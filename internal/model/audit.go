@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditOperation вид изменения подписки.
+type AuditOperation string
+
+const (
+	AuditCreate  AuditOperation = "create"
+	AuditUpdate  AuditOperation = "update"
+	AuditDelete  AuditOperation = "delete"
+	AuditRestore AuditOperation = "restore"
+	AuditCancel  AuditOperation = "cancel"
+	// AuditPurge окончательное удаление мягко удалённой подписки
+	AuditPurge AuditOperation = "purge"
+	// Изменения цен и пауз записываются под ключами price_change и pause
+	AuditPriceChange       AuditOperation = "price_change"
+	AuditPriceChangeDelete AuditOperation = "price_change_delete"
+	AuditPause             AuditOperation = "pause"
+	AuditResume            AuditOperation = "resume"
+)
+
+func (o AuditOperation) Valid() bool {
+	switch o {
+	case AuditCreate, AuditUpdate, AuditDelete, AuditRestore, AuditCancel, AuditPurge,
+		AuditPriceChange, AuditPriceChangeDelete, AuditPause, AuditResume:
+		return true
+	}
+	return false
+}
+
+// AuditRecord запись журнала изменений подписки. Журнал только дополняется
+// и не зависит от подписки: записи переживают её окончательное удаление.
+type AuditRecord struct {
+	ID             uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SubscriptionID uuid.UUID      `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	UserID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Actor          string         `gorm:"not null" json:"actor"`
+	Operation      AuditOperation `gorm:"type:text;not null" json:"operation"`
+	// Changes изменённые поля подписки в JSON-представлении: значения до и после
+	Changes   map[string]FieldChange `gorm:"type:jsonb;serializer:json;not null" json:"changes"`
+	CreatedAt time.Time              `gorm:"index" json:"created_at"`
+}
+
+func (AuditRecord) TableName() string {
+	return "audit_log"
+}
+
+// FieldChange значение поля до и после изменения; null — поле отсутствовало.
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditFilter условия отбора записей журнала. Пустые поля не ограничивают выборку.
+type AuditFilter struct {
+	SubscriptionID *uuid.UUID
+	UserID         *uuid.UUID
+	Actor          *string
+	Operation      *AuditOperation
+	// Since и Until ограничивают время записи полуинтервалом [Since, Until)
+	Since *time.Time
+	Until *time.Time
+}
+
+type AuditList struct {
+	Total int64         `json:"total"`
+	Items []AuditRecord `json:"items"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import "github.com/google/uuid"
+
+// BatchOp операция над подпиской в пакетном запросе.
+type BatchOp string
+
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+func (op BatchOp) Valid() bool {
+	switch op {
+	case BatchCreate, BatchUpdate, BatchDelete:
+		return true
+	}
+	return false
+}
+
+// BatchMode режим выполнения пакета.
+type BatchMode string
+
+const (
+	// BatchAtomic применяет все операции в одной транзакции: ошибка любой отменяет весь пакет
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort применяет операции независимо и сообщает результат каждой
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+func (m BatchMode) Valid() bool {
+	return m == BatchAtomic || m == BatchBestEffort
+}
+
+// BatchOperationReq represents a single operation of a batch request
+// swagger:model
+type BatchOperationReq struct {
+	// Op operation kind
+	// required: true
+	Op BatchOp `json:"op" example:"create" enums:"create,update,delete"`
+	// ID subscription to update or delete
+	ID *uuid.UUID `json:"id,omitempty"`
+	// Version optional expected subscription version for update, like If-Match
+	Version int64 `json:"version,omitempty" example:"3"`
+	// Subscription full subscription data for create and update
+	Subscription *SubscriptionReq `json:"subscription,omitempty"`
+}
+
+// BatchReq represents a batch of subscription operations
+// swagger:model
+type BatchReq struct {
+	// Mode atomic applies all operations or none (default), best_effort applies each independently
+	Mode BatchMode `json:"mode,omitempty" example:"atomic" enums:"atomic,best_effort"`
+	// Operations operations in the order they are applied
+	// required: true
+	Operations []BatchOperationReq `json:"operations" binding:"required"`
+}
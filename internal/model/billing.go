@@ -0,0 +1,155 @@
+package model
+
+import "time"
+
+// BillingPeriod единица периода списания. Вместе с BillingInterval задаёт цикл:
+// например, month и 3 — раз в квартал, week и 2 — раз в две недели.
+type BillingPeriod string
+
+const (
+	BillingDay     BillingPeriod = "day"
+	BillingWeek    BillingPeriod = "week"
+	BillingMonth   BillingPeriod = "month"
+	BillingQuarter BillingPeriod = "quarter"
+	BillingYear    BillingPeriod = "year"
+)
+
+// Границы дат подписки. Вместе с MaxInterval они не дают счётчику списаний переполниться
+// и ограничивают число списаний, которые перебирают итераторы.
+const (
+	MinYear = 1970
+	MaxYear = 2100
+)
+
+func (p BillingPeriod) Valid() bool {
+	switch p {
+	case BillingDay, BillingWeek, BillingMonth, BillingQuarter, BillingYear:
+		return true
+	}
+	return false
+}
+
+// MaxInterval наибольший BillingInterval для периода p — примерно десять лет между списаниями.
+func (p BillingPeriod) MaxInterval() int {
+	switch p {
+	case BillingDay:
+		return 3660
+	case BillingWeek:
+		return 520
+	case BillingQuarter:
+		return 40
+	case BillingYear:
+		return 10
+	default:
+		return 120
+	}
+}
+
+// addPeriods сдвигает t на n периодов p.
+func (p BillingPeriod) addPeriods(t time.Time, n int) time.Time {
+	switch p {
+	case BillingDay:
+		return t.AddDate(0, 0, n)
+	case BillingWeek:
+		return t.AddDate(0, 0, 7*n)
+	case BillingQuarter:
+		return t.AddDate(0, 3*n, 0)
+	case BillingYear:
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, n, 0)
+	}
+}
+
+// ChargeAt возвращает дату n-го списания (n = 0 — дата начала подписки).
+// Интервал вне допустимых границ приводится к ним, чтобы n*interval не переполнялось.
+func (s *Subscription) ChargeAt(n int) time.Time {
+	interval := min(max(s.BillingInterval, 1), s.BillingPeriod.MaxInterval())
+	return s.BillingPeriod.addPeriods(s.StartDate, n*interval)
+}
+
+// ActiveUntil возвращает момент окончания подписки (не включительно):
+// подписка действует до конца месяца EndDate. Для бессрочной — nil.
+func (s *Subscription) ActiveUntil() *time.Time {
+	if s.EndDate == nil {
+		return nil
+	}
+	until := s.EndDate.AddDate(0, 1, 0)
+	return &until
+}
+
+// ChargesBetween возвращает даты списаний в полуинтервале [from, until),
+// не выходящие за срок действия подписки и за MaxYear.
+func (s *Subscription) ChargesBetween(from, until time.Time) []time.Time {
+	if end := s.ActiveUntil(); end != nil && end.Before(until) {
+		until = *end
+	}
+	if horizon := time.Date(MaxYear+1, 1, 1, 0, 0, 0, 0, time.UTC); horizon.Before(until) {
+		until = horizon
+	}
+
+	var charges []time.Time
+	for n := s.firstChargeFrom(from); ; n++ {
+		at := s.ChargeAt(n)
+		if !at.Before(until) {
+			break
+		}
+		charges = append(charges, at)
+	}
+	return charges
+}
+
+// firstChargeFrom возвращает номер первого списания не раньше from. Номер оценивается
+// по длине периода снизу, поэтому перебор после оценки занимает несколько шагов.
+func (s *Subscription) firstChargeFrom(from time.Time) int {
+	if !from.After(s.StartDate) {
+		return 0
+	}
+	interval := min(max(s.BillingInterval, 1), s.BillingPeriod.MaxInterval())
+	var n int
+	switch s.BillingPeriod {
+	case BillingDay, BillingWeek:
+		days := int(from.Sub(s.StartDate).Hours() / 24)
+		if s.BillingPeriod == BillingWeek {
+			days /= 7
+		}
+		n = days / interval
+	default:
+		months := (from.Year()-s.StartDate.Year())*12 + int(from.Month()) - int(s.StartDate.Month())
+		switch s.BillingPeriod {
+		case BillingQuarter:
+			months /= 3
+		case BillingYear:
+			months /= 12
+		}
+		n = months / interval
+	}
+	n = max(n-1, 0)
+	for s.ChargeAt(n).Before(from) {
+		n++
+	}
+	return n
+}
+
+// InTrial сообщает, приходится ли списание at на бесплатный пробный период.
+func (s *Subscription) InTrial(at time.Time) bool {
+	return s.TrialEndDate != nil && at.Before(*s.TrialEndDate)
+}
+
+// NextPaidCharge возвращает дату первого платного списания не раньше from
+// в пределах срока действия подписки.
+func (s *Subscription) NextPaidCharge(from time.Time) (time.Time, bool) {
+	if s.TrialEndDate != nil && s.TrialEndDate.After(from) {
+		from = *s.TrialEndDate
+	}
+	end := s.ActiveUntil()
+	for n := 0; ; n++ {
+		at := s.ChargeAt(n)
+		if end != nil && !at.Before(*end) {
+			return time.Time{}, false
+		}
+		if !at.Before(from) {
+			return at, true
+		}
+	}
+}
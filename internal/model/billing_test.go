@@ -0,0 +1,177 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestChargesBetween(t *testing.T) {
+	tests := []struct {
+		name        string
+		sub         Subscription
+		from, until time.Time
+		want        []time.Time
+	}{
+		{
+			name:  "monthly within window",
+			sub:   Subscription{StartDate: date(2025, 1, 1), BillingPeriod: BillingMonth, BillingInterval: 1},
+			from:  date(2025, 3, 1),
+			until: date(2025, 6, 1),
+			want:  []time.Time{date(2025, 3, 1), date(2025, 4, 1), date(2025, 5, 1)},
+		},
+		{
+			name:  "window before start",
+			sub:   Subscription{StartDate: date(2025, 6, 1), BillingPeriod: BillingMonth, BillingInterval: 1},
+			from:  date(2025, 1, 1),
+			until: date(2025, 6, 1),
+			want:  nil,
+		},
+		{
+			name:  "quarterly",
+			sub:   Subscription{StartDate: date(2025, 2, 1), BillingPeriod: BillingQuarter, BillingInterval: 1},
+			from:  date(2025, 1, 1),
+			until: date(2026, 1, 1),
+			want:  []time.Time{date(2025, 2, 1), date(2025, 5, 1), date(2025, 8, 1), date(2025, 11, 1)},
+		},
+		{
+			name:  "every two weeks",
+			sub:   Subscription{StartDate: date(2025, 1, 1), BillingPeriod: BillingWeek, BillingInterval: 2},
+			from:  date(2025, 1, 10),
+			until: date(2025, 2, 15),
+			want:  []time.Time{date(2025, 1, 15), date(2025, 1, 29), date(2025, 2, 12)},
+		},
+		{
+			name:  "yearly from far after start",
+			sub:   Subscription{StartDate: date(2020, 6, 1), BillingPeriod: BillingYear, BillingInterval: 1},
+			from:  date(2024, 1, 1),
+			until: date(2026, 1, 1),
+			want:  []time.Time{date(2024, 6, 1), date(2025, 6, 1)},
+		},
+		{
+			name:  "every two months skips odd months",
+			sub:   Subscription{StartDate: date(2025, 1, 1), BillingPeriod: BillingMonth, BillingInterval: 2},
+			from:  date(2025, 2, 1),
+			until: date(2025, 8, 1),
+			want:  []time.Time{date(2025, 3, 1), date(2025, 5, 1), date(2025, 7, 1)},
+		},
+		{
+			name:  "end date includes its month",
+			sub:   Subscription{StartDate: date(2025, 1, 1), EndDate: ptr(date(2025, 3, 1)), BillingPeriod: BillingMonth, BillingInterval: 1},
+			from:  date(2025, 1, 1),
+			until: date(2026, 1, 1),
+			want:  []time.Time{date(2025, 1, 1), date(2025, 2, 1), date(2025, 3, 1)},
+		},
+		{
+			name:  "legacy zero interval counts as one",
+			sub:   Subscription{StartDate: date(2025, 1, 1), BillingPeriod: BillingMonth},
+			from:  date(2025, 1, 1),
+			until: date(2025, 3, 1),
+			want:  []time.Time{date(2025, 1, 1), date(2025, 2, 1)},
+		},
+		{
+			name:  "huge interval is clamped to ten years",
+			sub:   Subscription{StartDate: date(2025, 1, 1), BillingPeriod: BillingMonth, BillingInterval: 1 << 40},
+			from:  date(2025, 2, 1),
+			until: date(2046, 1, 1),
+			want:  []time.Time{date(2035, 1, 1), date(2045, 1, 1)},
+		},
+		{
+			name:  "daily far from start",
+			sub:   Subscription{StartDate: date(1970, 1, 1), BillingPeriod: BillingDay, BillingInterval: 1},
+			from:  date(2100, 12, 30),
+			until: date(2101, 1, 1),
+			want:  []time.Time{date(2100, 12, 30), date(2100, 12, 31)},
+		},
+		{
+			name:  "stops at horizon",
+			sub:   Subscription{StartDate: date(2100, 12, 30), BillingPeriod: BillingDay, BillingInterval: 1},
+			from:  date(2100, 12, 1),
+			until: date(9999, 1, 1),
+			want:  []time.Time{date(2100, 12, 30), date(2100, 12, 31)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sub.ChargesBetween(tt.from, tt.until))
+		})
+	}
+}
+
+// TestFirstChargeFrom сверяет оценку номера списания с прямым перебором.
+func TestFirstChargeFrom(t *testing.T) {
+	periods := []BillingPeriod{BillingDay, BillingWeek, BillingMonth, BillingQuarter, BillingYear}
+	starts := []time.Time{date(2024, 1, 31), date(2024, 2, 29), date(2025, 7, 1)}
+
+	for _, period := range periods {
+		for interval := 1; interval <= 3; interval++ {
+			for _, start := range starts {
+				sub := Subscription{StartDate: start, BillingPeriod: period, BillingInterval: interval}
+				for from := start.AddDate(0, -1, 0); from.Before(start.AddDate(4, 0, 0)); from = from.AddDate(0, 0, 5) {
+					want := 0
+					for sub.ChargeAt(want).Before(from) {
+						want++
+					}
+					if got := sub.firstChargeFrom(from); got != want {
+						t.Errorf("%s/%d from %s start %s: got %d, want %d",
+							period, interval, from.Format(time.DateOnly), start.Format(time.DateOnly), got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestInTrial(t *testing.T) {
+	trial := Subscription{StartDate: date(2025, 1, 1), TrialEndDate: ptr(date(2025, 3, 1))}
+
+	assert.True(t, trial.InTrial(date(2025, 1, 1)))
+	assert.True(t, trial.InTrial(date(2025, 2, 28)))
+	assert.False(t, trial.InTrial(date(2025, 3, 1)), "first paid day")
+	noTrial := Subscription{StartDate: date(2025, 1, 1)}
+	assert.False(t, noTrial.InTrial(date(2025, 1, 1)))
+}
+
+func TestNextPaidCharge(t *testing.T) {
+	monthly := Subscription{StartDate: date(2025, 1, 1), BillingPeriod: BillingMonth, BillingInterval: 1}
+	tests := []struct {
+		name   string
+		sub    Subscription
+		from   time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "next month", sub: monthly, from: date(2025, 3, 15), want: date(2025, 4, 1), wantOK: true},
+		{name: "charge day itself", sub: monthly, from: date(2025, 4, 1), want: date(2025, 4, 1), wantOK: true},
+		{
+			name:   "after trial",
+			sub:    Subscription{StartDate: date(2025, 1, 1), TrialEndDate: ptr(date(2025, 3, 10)), BillingPeriod: BillingMonth, BillingInterval: 1},
+			from:   date(2025, 1, 15),
+			want:   date(2025, 4, 1),
+			wantOK: true,
+		},
+		{
+			name: "ended",
+			sub:  Subscription{StartDate: date(2025, 1, 1), EndDate: ptr(date(2025, 3, 1)), BillingPeriod: BillingMonth, BillingInterval: 1},
+			from: date(2025, 3, 15),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.sub.NextPaidCharge(tt.from)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
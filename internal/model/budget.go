@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Budget месячный лимит расходов пользователя: общий (пустая Category)
+// или по одной категории подписок.
+type Budget struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_budgets_user_category" json:"user_id"`
+	Category string    `gorm:"not null;default:'';uniqueIndex:idx_budgets_user_category" json:"category,omitempty"`
+	Amount   int       `gorm:"type:bigint;not null" json:"amount"` // в минимальных единицах валюты бюджета
+	// Currency код валюты ISO 4217, в которую переводятся списания при сравнении с лимитом
+	Currency  string    `gorm:"type:char(3);not null;default:RUB" json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BudgetReq represents a budget creation or update request
+// swagger:model
+type BudgetReq struct {
+	// UserID owner of the budget (UUID)
+	// required: true
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	// Category subscription category the budget applies to, empty for an overall budget
+	Category string `json:"category,omitempty" example:"entertainment"`
+	// Amount monthly limit in minor currency units
+	// required: true
+	Amount *int `json:"amount" binding:"required" example:"150000"`
+	// Currency ISO 4217 currency code of the limit (default RUB)
+	Currency string `json:"currency,omitempty" example:"RUB"`
+}
+
+// BudgetStatus расход по бюджету за месяц: все списания месяца, включая ещё
+// не наступившие, переведённые в валюту бюджета.
+type BudgetStatus struct {
+	Budget    Budget    `json:"budget"`
+	Month     time.Time `json:"month"` // первое число месяца
+	Spent     int       `json:"spent"`
+	Remaining int       `json:"remaining"` // отрицательный, если лимит превышен
+	Percent   int       `json:"percent"`   // доля израсходованного лимита, %
+	Exceeded  bool      `json:"exceeded"`
+}
+
+// BudgetAlert событие о превышении бюджета после изменения подписки.
+type BudgetAlert struct {
+	Status         BudgetStatus
+	SubscriptionID uuid.UUID
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RenewalSeries серия будущих списаний подписки по одной цене: первое списание Start,
+// следующие — каждые Interval периодов Period, последнее — Until (nil — без окончания).
+// Except — списания серии, пропускаемые из-за пауз.
+type RenewalSeries struct {
+	SubscriptionID uuid.UUID
+	ServiceName    string
+	Amount         int // в минимальных единицах валюты
+	Currency       string
+	Start          time.Time
+	Period         BillingPeriod
+	Interval       int
+	Until          *time.Time
+	Except         []time.Time
+	// PriceFrom начало действия цены серии, если она задана изменением цены
+	PriceFrom *time.Time
+}
@@ -0,0 +1,49 @@
+package model
+
+// CancelReason код причины отмены подписки.
+type CancelReason string
+
+const (
+	CancelTooExpensive    CancelReason = "too_expensive"
+	CancelNotUsing        CancelReason = "not_using"
+	CancelSwitchedService CancelReason = "switched_service"
+	CancelMissingFeatures CancelReason = "missing_features"
+	CancelTechnicalIssues CancelReason = "technical_issues"
+	CancelTemporary       CancelReason = "temporary"
+	CancelOther           CancelReason = "other"
+	// CancelUnspecified записывается, если причина не указана
+	CancelUnspecified CancelReason = "unspecified"
+)
+
+func (r CancelReason) Valid() bool {
+	switch r {
+	case CancelTooExpensive, CancelNotUsing, CancelSwitchedService, CancelMissingFeatures,
+		CancelTechnicalIssues, CancelTemporary, CancelOther, CancelUnspecified:
+		return true
+	}
+	return false
+}
+
+// CancelEndOfPeriod значение effective_date: отмена с окончанием оплаченного периода.
+const CancelEndOfPeriod = "end_of_period"
+
+// CancelReq represents a subscription cancellation request
+// swagger:model
+type CancelReq struct {
+	// EffectiveDate last active month in MM-YYYY format, or end_of_period to stop before the next paid charge (default)
+	EffectiveDate string `json:"effective_date,omitempty" example:"end_of_period"`
+	// Reason optional cancellation reason code
+	Reason CancelReason `json:"reason,omitempty" example:"too_expensive" enums:"too_expensive,not_using,switched_service,missing_features,technical_issues,temporary,other"`
+}
+
+// ReasonCount число отмен с одной причиной.
+type ReasonCount struct {
+	Reason CancelReason `json:"reason"`
+	Count  int64        `json:"count"`
+}
+
+// CancellationStats сводка причин отмен, по убыванию числа отмен.
+type CancellationStats struct {
+	Total   int64         `json:"total"`
+	Reasons []ReasonCount `json:"reasons"`
+}
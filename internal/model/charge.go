@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Charge списание по подписке: прошедшее или ожидаемое.
+type Charge struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceName    string    `json:"service_name"`
+	Date           time.Time `gorm:"column:charged_at" json:"date"`
+	Amount         int       `json:"amount"` // в минимальных единицах валюты
+	Currency       string    `json:"currency"`
+}
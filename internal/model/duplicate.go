@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// DuplicateReason почему две подписки похожи на одну и ту же.
+type DuplicateReason string
+
+const (
+	// DuplicateSameService совпадают название сервиса без учёта регистра и пробелов или запись каталога
+	DuplicateSameService DuplicateReason = "same_service"
+	// DuplicateSimilar почти одинаковые названия и цены при одном цикле оплаты
+	DuplicateSimilar DuplicateReason = "similar"
+)
+
+// Duplicate пара подписок пользователя, которые, похоже, записаны дважды
+// или оплачиваются одновременно.
+type Duplicate struct {
+	Reason        DuplicateReason `json:"reason" example:"same_service"`
+	Subscriptions []Subscription  `json:"subscriptions"`
+	// OverlapFrom первое число первого месяца, в котором действуют обе подписки
+	OverlapFrom time.Time `json:"overlap_from"`
+	// OverlapTo первое число последнего общего месяца, пусто — обе подписки бессрочные
+	OverlapTo *time.Time `json:"overlap_to,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionFilter условия отбора подписок для списка и подсчёта сумм.
+// Пустые поля не ограничивают выборку.
+type SubscriptionFilter struct {
+	UserID *uuid.UUID
+	// ServiceName оставляет подписки с этим названием без учёта регистра и пробелов;
+	// значение должно быть уже нормализовано NormalizeLabel
+	ServiceName *string
+	// ServiceID оставляет подписки, связанные с записью каталога, и несвязанные подписки,
+	// название которых совпадает с одним из её псевдонимов
+	ServiceID *uuid.UUID
+	Category  *string
+	// Tag оставляет подписки с этой меткой
+	Tag *string
+	// IncludeDeleted добавляет в выборку мягко удалённые подписки
+	IncludeDeleted bool
+	// ActiveIn оставляет подписки, действующие и не приостановленные в месяце ActiveIn
+	ActiveIn *time.Time
+}
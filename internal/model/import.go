@@ -0,0 +1,21 @@
+package model
+
+// ImportRowError ошибка в строке импортируемого файла.
+type ImportRowError struct {
+	// Row номер строки файла, заголовок — строка 1
+	Row     int    `json:"row" example:"3"`
+	Field   string `json:"field,omitempty" example:"start_date"`
+	Message string `json:"message" example:"invalid start_date format, expected MM-YYYY"`
+}
+
+// ImportResult итог импорта. Если в файле есть ошибки, ни одна строка не сохраняется.
+type ImportResult struct {
+	DryRun bool `json:"dry_run"`
+	// Rows число строк с данными
+	Rows int `json:"rows"`
+	// Valid число строк, прошедших проверку
+	Valid int `json:"valid"`
+	// Imported число сохранённых подписок
+	Imported int              `json:"imported"`
+	Errors   []ImportRowError `json:"errors"`
+}
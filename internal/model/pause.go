@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pause интервал приостановки подписки: месяцы с PausedFrom включительно
+// до ResumedAt не включительно. Открытая пауза (ResumedAt == nil) длится до возобновления.
+// Записи не удаляются при возобновлении и образуют историю пауз.
+type Pause struct {
+	ID             uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SubscriptionID uuid.UUID     `gorm:"type:uuid;not null;index;uniqueIndex:idx_pauses_open,where:resumed_at IS NULL" json:"subscription_id"`
+	Subscription   *Subscription `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	PausedFrom     time.Time     `gorm:"type:date;not null" json:"paused_from"`
+	ResumedAt      *time.Time    `gorm:"type:date" json:"resumed_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// PauseReq represents a request to pause a subscription
+// swagger:model
+type PauseReq struct {
+	// From first paused month in MM-YYYY format (default current month)
+	From *string `json:"from,omitempty" example:"08-2025"`
+}
+
+// ResumeReq represents a request to resume a paused subscription
+// swagger:model
+type ResumeReq struct {
+	// At first month charged again in MM-YYYY format (default current month, or the month after the pause start)
+	At *string `json:"at,omitempty" example:"11-2025"`
+}
+
+// Covers сообщает, приходится ли момент at на паузу.
+func (p Pause) Covers(at time.Time) bool {
+	return !at.Before(p.PausedFrom) && (p.ResumedAt == nil || at.Before(*p.ResumedAt))
+}
+
+// PausedAt сообщает, приостановлена ли подписка в момент at.
+func PausedAt(pauses []Pause, at time.Time) bool {
+	for _, p := range pauses {
+		if p.Covers(at) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPausedAt(t *testing.T) {
+	pauses := []Pause{
+		{PausedFrom: date(2025, 2, 1), ResumedAt: ptr(date(2025, 4, 1))},
+		{PausedFrom: date(2025, 8, 1)},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "before pauses", at: date(2025, 1, 1), want: false},
+		{name: "first paused month", at: date(2025, 2, 1), want: true},
+		{name: "inside closed pause", at: date(2025, 3, 15), want: true},
+		{name: "resume month is charged", at: date(2025, 4, 1), want: false},
+		{name: "between pauses", at: date(2025, 6, 1), want: false},
+		{name: "open pause", at: date(2030, 1, 1), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PausedAt(pauses, tt.at))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChange запланированное или прошедшее изменение цены подписки.
+// Цена действует для списаний начиная с EffectiveFrom до следующего изменения.
+type PriceChange struct {
+	ID             uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SubscriptionID uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_price_changes_subscription_date" json:"subscription_id"`
+	Subscription   *Subscription `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	EffectiveFrom  time.Time     `gorm:"type:date;not null;uniqueIndex:idx_price_changes_subscription_date" json:"effective_from"`
+	Price          int           `gorm:"type:bigint;not null" json:"price"` // в минимальных единицах валюты подписки
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// PriceChangeReq represents a request to schedule a price change
+// swagger:model
+type PriceChangeReq struct {
+	// EffectiveFrom month from which the new price applies, in MM-YYYY format
+	// required: true
+	EffectiveFrom string `json:"effective_from" binding:"required" example:"01-2026"`
+	// Price new price in minor currency units of the subscription
+	// required: true
+	Price *int `json:"price" binding:"required" example:"49900"`
+}
+
+// PriceAt возвращает цену, действующую на дату at: последнее изменение
+// с EffectiveFrom <= at или base, если изменений ещё не было.
+// changes должны быть отсортированы по EffectiveFrom.
+func PriceAt(base int, changes []PriceChange, at time.Time) int {
+	price := base
+	for _, pc := range changes {
+		if pc.EffectiveFrom.After(at) {
+			break
+		}
+		price = pc.Price
+	}
+	return price
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceAt(t *testing.T) {
+	changes := []PriceChange{
+		{EffectiveFrom: date(2025, 3, 1), Price: 1200},
+		{EffectiveFrom: date(2025, 6, 1), Price: 1500},
+	}
+
+	tests := []struct {
+		name    string
+		changes []PriceChange
+		at      time.Time
+		want    int
+	}{
+		{name: "no changes", at: date(2025, 6, 1), want: 1000},
+		{name: "before first change", changes: changes, at: date(2025, 2, 1), want: 1000},
+		{name: "on change date", changes: changes, at: date(2025, 3, 1), want: 1200},
+		{name: "between changes", changes: changes, at: date(2025, 5, 15), want: 1200},
+		{name: "after last change", changes: changes, at: date(2026, 1, 1), want: 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PriceAt(1000, tt.changes, tt.at))
+		})
+	}
+}
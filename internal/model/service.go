@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Service запись каталога сервисов с каноническим названием. Подписки, название
+// которых совпадает с одним из псевдонимов, при создании связываются с записью каталога.
+type Service struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name string    `gorm:"not null" json:"name"`
+	// Aliases нормализованные псевдонимы, всегда включают само название; хранятся в service_aliases
+	Aliases []string `gorm:"-" json:"aliases"`
+	// DefaultCategory категория для новых подписок, если она не указана явно
+	DefaultCategory string    `gorm:"not null;default:''" json:"default_category,omitempty"`
+	URL             string    `gorm:"not null;default:''" json:"url,omitempty"`
+	LogoURL         string    `gorm:"not null;default:''" json:"logo_url,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ServiceAlias нормализованный псевдоним сервиса. Псевдоним принадлежит только одному сервису.
+type ServiceAlias struct {
+	Alias     string    `gorm:"primaryKey"`
+	ServiceID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Service   *Service  `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ServiceReq represents a service catalog entry creation or update request
+// swagger:model
+type ServiceReq struct {
+	// Name canonical service name
+	// required: true
+	Name string `json:"name" binding:"required" example:"Netflix"`
+	// Aliases alternative spellings matched case- and whitespace-insensitively
+	Aliases []string `json:"aliases,omitempty" example:"netflix.com,нетфликс"`
+	// DefaultCategory category assigned to new subscriptions of this service
+	DefaultCategory string `json:"default_category,omitempty" example:"entertainment"`
+	// URL service website
+	URL string `json:"url,omitempty" example:"https://www.netflix.com"`
+	// LogoURL service logo image
+	LogoURL string `json:"logo_url,omitempty" example:"https://www.netflix.com/favicon.ico"`
+}
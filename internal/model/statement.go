@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatementCandidate повторяющееся списание из банковской выписки, похожее на подписку.
+type StatementCandidate struct {
+	// Subscription proposed subscription; edit it if needed and send it to /subscriptions/statement/confirm
+	Subscription SubscriptionReq `json:"subscription"`
+	// Merchant payee as it appears in the latest matching transaction
+	Merchant string `json:"merchant" example:"NETFLIX.COM 866-579-7172"`
+	// Charges dates of the matching charges
+	Charges []time.Time `json:"charges"`
+	// Ended charges stopped more than a billing period before the end of the statement, end_date is set to the last charge
+	Ended bool `json:"ended"`
+	// ExistingSubscriptionID user's subscription with the same service name, the candidate is probably already recorded
+	ExistingSubscriptionID *uuid.UUID `json:"existing_subscription_id,omitempty"`
+}
+
+// StatementAnalysis итог разбора выписки.
+type StatementAnalysis struct {
+	// Transactions number of transactions in the statement
+	Transactions int                  `json:"transactions"`
+	Candidates   []StatementCandidate `json:"candidates"`
+}
+
+// ConfirmCandidatesReq подписки, выбранные пользователем из кандидатов
+// swagger:model
+type ConfirmCandidatesReq struct {
+	Subscriptions []SubscriptionReq `json:"subscriptions" binding:"required"`
+}
@@ -2,16 +2,44 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"time"
 )
 
 type Subscription struct {
 	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id,omitempty"`
 	ServiceName string     `json:"service_name" db:"service_name"`
-	Price       int        `json:"price" db:"price"`
+	Price       int        `gorm:"type:bigint" json:"price" db:"price"` // в минимальных единицах валюты (копейки, центы)
 	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
 	StartDate   time.Time  `json:"start_date" db:"start_date"` // формат "07-2025"
 	EndDate     *time.Time `json:"end_date,omitempty" db:"end_date"`
+	// BillingPeriod и BillingInterval задают цикл списаний: раз в BillingInterval периодов
+	BillingPeriod   BillingPeriod `gorm:"type:text;not null;default:month" json:"billing_period" db:"billing_period"`
+	BillingInterval int           `gorm:"not null;default:1" json:"billing_interval" db:"billing_interval"`
+	// Currency код валюты ISO 4217
+	Currency string `gorm:"type:char(3);not null;default:RUB" json:"currency" db:"currency"`
+	// TrialEndDate первый платный день: списания до этой даты бесплатны
+	TrialEndDate *time.Time `gorm:"type:date" json:"trial_end_date,omitempty" db:"trial_end_date"`
+	// Category категория расходов в нижнем регистре, пусто — без категории
+	Category string `gorm:"not null;default:''" json:"category,omitempty" db:"category"`
+	// Tags имена меток по алфавиту, хранятся в таблицах tags и subscription_tags
+	Tags []string `gorm:"-" json:"tags,omitempty"`
+	// ServiceID запись каталога сервисов, с которой связана подписка
+	ServiceID *uuid.UUID `gorm:"type:uuid;index" json:"service_id,omitempty" db:"service_id"`
+	Service   *Service   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+	// CanceledAt момент отмены; подписка при этом хранится, а EndDate задаёт последний месяц
+	CanceledAt   *time.Time   `json:"canceled_at,omitempty" db:"canceled_at"`
+	CancelReason CancelReason `gorm:"type:text;not null;default:''" json:"cancel_reason,omitempty" db:"cancel_reason"`
+	// DeletedAt момент мягкого удаления: подписка скрыта из списков, но её прошлые
+	// списания остаются в суммах, пока запись не удалена окончательно
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string" format:"date-time" db:"deleted_at"`
+	// Version увеличивается при каждом изменении подписки, служит ETag для оптимистичных блокировок
+	Version int64 `gorm:"not null;default:1" json:"version" db:"version"`
+}
+
+// MarkDeleted помечает подписку мягко удалённой в момент at.
+func (s *Subscription) MarkDeleted(at time.Time) {
+	s.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
 }
 
 // SubscriptionReq represents a subscription creation request
@@ -20,9 +48,11 @@ type SubscriptionReq struct {
 	// ServiceName is the name of the subscription service
 	// required: true
 	ServiceName string `json:"service_name" binding:"required"`
-	// Price subscription price, must be positive
+	// Price subscription price in minor currency units (e.g. kopecks or cents), must be positive
 	// required: true
-	Price int `json:"price" binding:"required"`
+	Price int `json:"price" binding:"required" example:"39900"`
+	// Currency ISO 4217 currency code of the price (default RUB)
+	Currency string `json:"currency,omitempty" example:"RUB"`
 	// UserID owner of the subscription (UUID)
 	// required: true
 	UserID uuid.UUID `json:"user_id" binding:"required"`
@@ -31,9 +61,66 @@ type SubscriptionReq struct {
 	StartDate string `json:"start_date" binding:"required" example:"07-2025"`
 	// EndDate optional subscription end date in MM-YYYY format
 	EndDate *string `json:"end_date,omitempty"`
+	// BillingPeriod billing period unit: day, week, month, quarter or year (default month)
+	BillingPeriod string `json:"billing_period,omitempty" example:"month" enums:"day,week,month,quarter,year"`
+	// BillingInterval number of periods between charges, e.g. 2 with week means every two weeks (default 1, at most about ten years)
+	BillingInterval int `json:"billing_interval,omitempty" example:"1"`
+	// TrialEndDate optional first paid month in MM-YYYY format, charges before it are free
+	TrialEndDate *string `json:"trial_end_date,omitempty" example:"08-2025"`
+	// TrialMonths optional free trial length in months from start_date, alternative to trial_end_date
+	TrialMonths int `json:"trial_months,omitempty" example:"1"`
+	// Category optional spending category, e.g. entertainment (case-insensitive)
+	Category string `json:"category,omitempty" example:"entertainment"`
+	// Tags optional free-form labels (case-insensitive)
+	Tags []string `json:"tags,omitempty" example:"family,work"`
+	// ServiceID optional service catalog entry; when omitted, service_name is matched against catalog aliases
+	ServiceID *uuid.UUID `json:"service_id,omitempty"`
 }
 
 type SubscriptionList struct {
 	Total int64          `json:"total"`
 	Items []Subscription `json:"items"`
 }
+
+// Total суммарная стоимость подписок. ByCurrency — суммы в минимальных единицах
+// по исходным валютам; Total заполняется, если запрошен перевод в одну валюту
+// или все суммы уже в одной валюте.
+type Total struct {
+	Total      *int           `json:"total,omitempty"`
+	Currency   string         `json:"currency,omitempty"`
+	ByCurrency map[string]int `json:"by_currency"`
+}
+
+// BreakdownItem сумма списаний по сервису за месяц.
+type BreakdownItem struct {
+	Month       time.Time `json:"month"` // первое число месяца
+	ServiceName string    `json:"service_name"`
+	Amount      int       `json:"amount"` // в минимальных единицах валюты
+	Currency    string    `json:"currency"`
+}
+
+// MonthlyAmount сумма списаний в одной валюте за месяц.
+type MonthlyAmount struct {
+	Month    time.Time `json:"month"` // первое число месяца
+	Amount   int       `json:"amount"`
+	Currency string    `json:"currency"`
+}
+
+// CategoryAmount сумма списаний в одной валюте по категории.
+type CategoryAmount struct {
+	Category string `json:"category"`
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// CategoryTotal итог по категории; пустая категория — подписки без категории.
+type CategoryTotal struct {
+	Category string `json:"category"`
+	Total
+}
+
+// ForecastMonth прогноз списаний на месяц.
+type ForecastMonth struct {
+	Month time.Time `json:"month"` // первое число месяца
+	Total
+}
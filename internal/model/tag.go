@@ -0,0 +1,27 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Tag свободная метка подписок. Имя хранится в нормализованном виде и уникально.
+type Tag struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name string    `gorm:"not null;uniqueIndex" json:"name"`
+}
+
+// SubscriptionTag связь подписки с меткой (many-to-many).
+type SubscriptionTag struct {
+	SubscriptionID uuid.UUID     `gorm:"type:uuid;primaryKey"`
+	Subscription   *Subscription `gorm:"constraint:OnDelete:CASCADE"`
+	TagID          uuid.UUID     `gorm:"type:uuid;primaryKey;index"`
+	Tag            *Tag          `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// NormalizeLabel приводит категорию или метку к нижнему регистру без лишних пробелов,
+// чтобы "Work tools" и "work  tools" считались одним значением.
+func NormalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *repo) CreateAuditRecord(ctx context.Context, rec *model.AuditRecord) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if rec.ID == uuid.Nil {
+		rec.ID = uuid.New()
+	}
+	return mapErr(db.Create(rec).Error)
+}
+
+func (r *repo) ListAuditRecords(ctx context.Context, filter model.AuditFilter, limit, offset int) (*model.AuditList, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	baseQuery := applyAuditFilter(db.Model(&model.AuditRecord{}), filter)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	records := []model.AuditRecord{}
+	if err := baseQuery.
+		Order("created_at, id").
+		Limit(limit).
+		Offset(offset).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return &model.AuditList{
+		Total: total,
+		Items: records,
+	}, nil
+}
+
+// applyAuditFilter добавляет условия фильтра к запросу по таблице audit_log.
+func applyAuditFilter(q *gorm.DB, filter model.AuditFilter) *gorm.DB {
+	if filter.SubscriptionID != nil {
+		q = q.Where("subscription_id = ?", *filter.SubscriptionID)
+	}
+	if filter.UserID != nil {
+		q = q.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Actor != nil {
+		q = q.Where("actor = ?", *filter.Actor)
+	}
+	if filter.Operation != nil {
+		q = q.Where("operation = ?", *filter.Operation)
+	}
+	if filter.Since != nil {
+		q = q.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		q = q.Where("created_at < ?", *filter.Until)
+	}
+	return q
+}
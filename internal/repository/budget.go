@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *repo) CreateBudget(ctx context.Context, b *model.Budget) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	// Уникальный индекс не даёт завести два бюджета на одну категорию
+	return mapErr(db.Create(b).Error)
+}
+
+func (r *repo) GetBudget(ctx context.Context, id uuid.UUID) (*model.Budget, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var b model.Budget
+	if err := db.First(&b, "id = ?", id).Error; err != nil {
+		return nil, mapErr(err)
+	}
+	return &b, nil
+}
+
+func (r *repo) UpdateBudget(ctx context.Context, b *model.Budget) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Model(b).Select("*").Omit("id", "created_at").Updates(b)
+	if res.Error != nil {
+		return mapErr(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Delete(&model.Budget{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) ListBudgets(ctx context.Context, userID *uuid.UUID) ([]model.Budget, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	q := db.Order("user_id, category")
+	if userID != nil {
+		q = q.Where("user_id = ?", *userID)
+	}
+	budgets := []model.Budget{}
+	if err := q.Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
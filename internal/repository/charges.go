@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// chargeStepSQL — шаг между списаниями подписки s в виде интервала Postgres.
+const chargeStepSQL = `CASE s.billing_period
+	WHEN 'day' THEN make_interval(days => s.billing_interval)
+	WHEN 'week' THEN make_interval(weeks => s.billing_interval)
+	WHEN 'quarter' THEN make_interval(months => 3 * s.billing_interval)
+	WHEN 'year' THEN make_interval(years => s.billing_interval)
+	ELSE make_interval(months => s.billing_interval)
+END`
+
+// chargeAmountSQL — сумма списания c.charged_at подписки s: цена из последнего
+// изменения, вступившего в силу к дате списания, либо исходная цена подписки.
+const chargeAmountSQL = `COALESCE((
+	SELECT pc.price FROM price_changes pc
+	WHERE pc.subscription_id = s.id AND pc.effective_from <= c.charged_at
+	ORDER BY pc.effective_from DESC
+	LIMIT 1
+), s.price)`
+
+// chargesQuery возвращает запрос по списаниям подписок (s) с датой c.charged_at
+// в полуинтервале [from, until). Списания разворачиваются через generate_series
+// от даты начала с шагом цикла; подписка с end_date действует до конца этого месяца,
+// бессрочная — до openUntil, мягко удалённая — до момента удаления.
+// Списания, пришедшиеся на паузу или пробный период, пропускаются.
+func chargesQuery(db *gorm.DB, from, until, openUntil time.Time) *gorm.DB {
+	return db.Table("subscriptions AS s").
+		Joins(`CROSS JOIN LATERAL generate_series(s.start_date::timestamp, ?::timestamp, `+chargeStepSQL+`) AS c(charged_at)`, until).
+		Where("c.charged_at >= ? AND c.charged_at < ?", from, until).
+		Where("c.charged_at < COALESCE(s.end_date + INTERVAL '1 month', ?::timestamp)", openUntil).
+		Where("s.deleted_at IS NULL OR c.charged_at < s.deleted_at").
+		Where("s.trial_end_date IS NULL OR c.charged_at >= s.trial_end_date").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM pauses p
+			WHERE p.subscription_id = s.id AND p.paused_from <= c.charged_at
+				AND (p.resumed_at IS NULL OR c.charged_at < p.resumed_at)
+		)`)
+}
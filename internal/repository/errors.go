@@ -0,0 +1,12 @@
+package repository
+
+import "errors"
+
+// Ошибки хранилища, не зависящие от конкретной реализации. Реализации Repository
+// приводят к ним ошибки драйвера, чтобы слой usecase не знал о GORM.
+var (
+	ErrNotFound = errors.New("record not found")
+	ErrConflict = errors.New("record already exists")
+	// ErrVersionMismatch запись изменилась после того, как её прочитали
+	ErrVersionMismatch = errors.New("record version mismatch")
+)
@@ -0,0 +1,608 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"subscriptions/internal/model"
+)
+
+// memoryRepo хранит подписки в памяти процесса. Используется в тестах и в демо-режиме
+// (STORAGE=memory), повторяя поведение SQL-реализации.
+type memoryRepo struct {
+	// mu защищает состояние; внутри транзакции блокировку уже держит её владелец
+	mu rwLocker
+	*memoryState
+}
+
+// memoryState данные хранилища, общие для репозитория и открытых на нём транзакций.
+type memoryState struct {
+	seq    int64
+	subs   map[uuid.UUID]memoryEntry
+	prices map[uuid.UUID][]model.PriceChange
+	pauses map[uuid.UUID][]model.Pause
+	// budgets хранятся отдельно от подписок и не удаляются вместе с ними
+	budgets map[uuid.UUID]model.Budget
+	// services каталог сервисов с псевдонимами
+	services map[uuid.UUID]model.Service
+	// audit журнал изменений в порядке добавления
+	audit []model.AuditRecord
+}
+
+type memoryEntry struct {
+	seq int64
+	sub model.Subscription
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepo{
+		mu: &sync.RWMutex{},
+		memoryState: &memoryState{
+			subs:   make(map[uuid.UUID]memoryEntry),
+			prices: make(map[uuid.UUID][]model.PriceChange),
+			pauses: make(map[uuid.UUID][]model.Pause),
+
+			budgets:  make(map[uuid.UUID]model.Budget),
+			services: make(map[uuid.UUID]model.Service),
+		},
+	}
+}
+
+func (r *memoryRepo) Create(ctx context.Context, sub *model.Subscription) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if sub.ID == uuid.Nil {
+		sub.ID = uuid.New()
+	}
+	if _, ok := r.subs[sub.ID]; ok {
+		return ErrConflict
+	}
+	sub.Version = 1
+	r.seq++
+	r.subs[sub.ID] = memoryEntry{seq: r.seq, sub: cloneSubscription(*sub)}
+	return nil
+}
+
+func (r *memoryRepo) CreateBatch(ctx context.Context, subs []*model.Subscription) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Как и транзакция в SQL, пакет сохраняется целиком или не сохраняется вовсе
+	ids := make(map[uuid.UUID]bool, len(subs))
+	for _, sub := range subs {
+		if sub.ID == uuid.Nil {
+			sub.ID = uuid.New()
+		}
+		if _, ok := r.subs[sub.ID]; ok || ids[sub.ID] {
+			return ErrConflict
+		}
+		ids[sub.ID] = true
+	}
+	for _, sub := range subs {
+		sub.Version = 1
+		r.seq++
+		r.subs[sub.ID] = memoryEntry{seq: r.seq, sub: cloneSubscription(*sub)}
+	}
+	return nil
+}
+
+func (r *memoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.live(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	sub := cloneSubscription(e.sub)
+	return &sub, nil
+}
+
+func (r *memoryRepo) GetDeleted(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.subs[id]
+	if !ok || !e.sub.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	sub := cloneSubscription(e.sub)
+	return &sub, nil
+}
+
+func (r *memoryRepo) Update(ctx context.Context, sub *model.Subscription) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.live(sub.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	if e.sub.Version != sub.Version {
+		return ErrVersionMismatch
+	}
+	sub.Version++
+	canceledAt, reason := e.sub.CanceledAt, e.sub.CancelReason
+	e.sub = cloneSubscription(*sub)
+	e.sub.CanceledAt, e.sub.CancelReason = canceledAt, reason
+	r.subs[sub.ID] = e
+	return nil
+}
+
+func (r *memoryRepo) Cancel(ctx context.Context, sub *model.Subscription) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.live(sub.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	if e.sub.Version != sub.Version {
+		return ErrVersionMismatch
+	}
+	sub.Version++
+	canceled := cloneSubscription(*sub)
+	e.sub.EndDate, e.sub.CanceledAt, e.sub.CancelReason = canceled.EndDate, canceled.CanceledAt, canceled.CancelReason
+	e.sub.Version = sub.Version
+	r.subs[sub.ID] = e
+	return nil
+}
+
+func (r *memoryRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.live(id)
+	if !ok {
+		return ErrNotFound
+	}
+	e.sub.MarkDeleted(time.Now())
+	r.subs[id] = e
+	return nil
+}
+
+func (r *memoryRepo) Restore(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.subs[id]
+	if !ok || !e.sub.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	e.sub.DeletedAt = gorm.DeletedAt{}
+	e.sub.Version++
+	r.subs[id] = e
+	return nil
+}
+
+func (r *memoryRepo) PurgeDeleted(ctx context.Context, before time.Time) ([]model.Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged []model.Subscription
+	for id, e := range r.subs {
+		if !e.sub.DeletedAt.Valid || !e.sub.DeletedAt.Time.Before(before) {
+			continue
+		}
+		delete(r.subs, id)
+		delete(r.prices, id)
+		delete(r.pauses, id)
+		purged = append(purged, e.sub)
+	}
+	return purged, nil
+}
+
+func (r *memoryRepo) List(ctx context.Context, filter model.SubscriptionFilter, limit, offset int) (*model.SubscriptionList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := r.filter(filter)
+	total := int64(len(matched))
+
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	matched = matched[offset:]
+	if limit >= 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+
+	items := make([]model.Subscription, 0, len(matched))
+	for _, e := range matched {
+		items = append(items, cloneSubscription(e.sub))
+	}
+
+	return &model.SubscriptionList{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+func (r *memoryRepo) ListInBatches(ctx context.Context, filter model.SubscriptionFilter, batchSize int, fn func(batch []model.Subscription) error) error {
+	var after int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, last := r.listAfter(filter, after, batchSize)
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = last
+	}
+}
+
+// listAfter возвращает до limit подписок, добавленных после записи с номером after,
+// и номер последней из них. Блокировка держится только на время чтения порции.
+func (r *memoryRepo) listAfter(filter model.SubscriptionFilter, after int64, limit int) ([]model.Subscription, int64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var batch []model.Subscription
+	for _, e := range r.filter(filter) {
+		if e.seq <= after {
+			continue
+		}
+		batch = append(batch, cloneSubscription(e.sub))
+		after = e.seq
+		if len(batch) == limit {
+			break
+		}
+	}
+	return batch, after
+}
+
+func (r *memoryRepo) CalculateTotal(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := make(map[string]int)
+	for _, ch := range r.pastCharges(filter, from, to) {
+		totals[ch.Currency] += ch.Amount
+	}
+
+	return totals, nil
+}
+
+func (r *memoryRepo) CalculateTotalByCategory(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) ([]model.CategoryAmount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type key struct {
+		category string
+		currency string
+	}
+	sums := make(map[key]int)
+	for _, ch := range r.pastCharges(filter, from, to) {
+		sums[key{r.subs[ch.SubscriptionID].sub.Category, ch.Currency}] += ch.Amount
+	}
+
+	amounts := make([]model.CategoryAmount, 0, len(sums))
+	for k, amount := range sums {
+		amounts = append(amounts, model.CategoryAmount{Category: k.category, Amount: amount, Currency: k.currency})
+	}
+	sort.Slice(amounts, func(i, j int) bool {
+		if amounts[i].Category != amounts[j].Category {
+			return amounts[i].Category < amounts[j].Category
+		}
+		return amounts[i].Currency < amounts[j].Currency
+	})
+	return amounts, nil
+}
+
+func (r *memoryRepo) CalculateBreakdown(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) ([]model.BreakdownItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type key struct {
+		month       time.Time
+		serviceName string
+		currency    string
+	}
+	sums := make(map[key]int)
+	for _, ch := range r.pastCharges(filter, from, to) {
+		sums[key{monthOf(ch.Date), ch.ServiceName, ch.Currency}] += ch.Amount
+	}
+
+	items := make([]model.BreakdownItem, 0, len(sums))
+	for k, amount := range sums {
+		items = append(items, model.BreakdownItem{Month: k.month, ServiceName: k.serviceName, Amount: amount, Currency: k.currency})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if !a.Month.Equal(b.Month) {
+			return a.Month.Before(b.Month)
+		}
+		if a.ServiceName != b.ServiceName {
+			return a.ServiceName < b.ServiceName
+		}
+		return a.Currency < b.Currency
+	})
+	return items, nil
+}
+
+func (r *memoryRepo) ForecastByMonth(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.MonthlyAmount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type key struct {
+		month    time.Time
+		currency string
+	}
+	sums := make(map[key]int)
+	for _, e := range r.filter(filter) {
+		for _, ch := range r.charges(e.sub, from, until) {
+			sums[key{monthOf(ch.Date), ch.Currency}] += ch.Amount
+		}
+	}
+
+	amounts := make([]model.MonthlyAmount, 0, len(sums))
+	for k, amount := range sums {
+		amounts = append(amounts, model.MonthlyAmount{Month: k.month, Amount: amount, Currency: k.currency})
+	}
+	sort.Slice(amounts, func(i, j int) bool {
+		if !amounts[i].Month.Equal(amounts[j].Month) {
+			return amounts[i].Month.Before(amounts[j].Month)
+		}
+		return amounts[i].Currency < amounts[j].Currency
+	})
+	return amounts, nil
+}
+
+func (r *memoryRepo) ListCharges(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.Charge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	charges := []model.Charge{}
+	for _, e := range r.filter(filter) {
+		charges = append(charges, r.charges(e.sub, from, until)...)
+	}
+	sort.SliceStable(charges, func(i, j int) bool {
+		if !charges[i].Date.Equal(charges[j].Date) {
+			return charges[i].Date.Before(charges[j].Date)
+		}
+		return charges[i].ServiceName < charges[j].ServiceName
+	})
+	return charges, nil
+}
+
+func (r *memoryRepo) ListTrialsEnding(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := []model.Subscription{}
+	for _, e := range r.filter(filter) {
+		trialEnd := e.sub.TrialEndDate
+		if trialEnd == nil || trialEnd.Before(from) || !trialEnd.Before(until) {
+			continue
+		}
+		if end := e.sub.ActiveUntil(); end != nil && !end.After(*trialEnd) {
+			continue
+		}
+		subs = append(subs, cloneSubscription(e.sub))
+	}
+	sort.SliceStable(subs, func(i, j int) bool { return subs[i].TrialEndDate.Before(*subs[j].TrialEndDate) })
+	return subs, nil
+}
+
+func (r *memoryRepo) CountCancelReasons(ctx context.Context, filter model.SubscriptionFilter, from, until *time.Time) ([]model.ReasonCount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byReason := make(map[model.CancelReason]int64)
+	for _, e := range r.filter(filter) {
+		at := e.sub.CanceledAt
+		if at == nil || (from != nil && at.Before(*from)) || (until != nil && !at.Before(*until)) {
+			continue
+		}
+		byReason[e.sub.CancelReason]++
+	}
+
+	counts := make([]model.ReasonCount, 0, len(byReason))
+	for reason, count := range byReason {
+		counts = append(counts, model.ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Reason < counts[j].Reason
+	})
+	return counts, nil
+}
+
+// pastCharges повторяет выборку CalculateTotal из SQL-реализации: списания в месяцах
+// [from, to], у бессрочных подписок — только до текущего момента.
+// Вызывающий должен держать блокировку.
+func (r *memoryRepo) pastCharges(filter model.SubscriptionFilter, from, to time.Time) []model.Charge {
+	now := time.Now()
+	until := to.AddDate(0, 1, 0)
+
+	var out []model.Charge
+	for _, e := range r.filter(filter) {
+		subUntil := until
+		if e.sub.EndDate == nil && now.Before(subUntil) {
+			subUntil = now
+		}
+		out = append(out, r.charges(e.sub, from, subUntil)...)
+	}
+	return out
+}
+
+// charges разворачивает платные списания подписки в полуинтервале [from, until)
+// так же, как chargesQuery: без пробного периода, пауз и списаний после удаления,
+// по действующей цене.
+// Вызывающий должен держать блокировку.
+func (r *memoryRepo) charges(sub model.Subscription, from, until time.Time) []model.Charge {
+	var out []model.Charge
+	for _, at := range sub.ChargesBetween(from, until) {
+		if sub.DeletedAt.Valid && !at.Before(sub.DeletedAt.Time) {
+			break
+		}
+		if sub.InTrial(at) || model.PausedAt(r.pauses[sub.ID], at) {
+			continue
+		}
+		out = append(out, model.Charge{
+			SubscriptionID: sub.ID,
+			ServiceName:    sub.ServiceName,
+			Date:           at,
+			Amount:         model.PriceAt(sub.Price, r.prices[sub.ID], at),
+			Currency:       sub.Currency,
+		})
+	}
+	return out
+}
+
+// filter возвращает записи, подходящие под фильтры, в порядке добавления.
+// Вызывающий должен держать блокировку.
+func (r *memoryRepo) filter(filter model.SubscriptionFilter) []memoryEntry {
+	var out []memoryEntry
+	for _, e := range r.subs {
+		if e.sub.DeletedAt.Valid && !filter.IncludeDeleted {
+			continue
+		}
+		if filter.UserID != nil && e.sub.UserID != *filter.UserID {
+			continue
+		}
+		if filter.ServiceName != nil && model.NormalizeLabel(e.sub.ServiceName) != *filter.ServiceName {
+			continue
+		}
+		if filter.ServiceID != nil && !r.ofService(e.sub, *filter.ServiceID) {
+			continue
+		}
+		if filter.Category != nil && e.sub.Category != *filter.Category {
+			continue
+		}
+		if filter.Tag != nil && !slices.Contains(e.sub.Tags, *filter.Tag) {
+			continue
+		}
+		if filter.ActiveIn != nil && !r.activeIn(e.sub, *filter.ActiveIn) {
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].seq < out[j].seq })
+	return out
+}
+
+// ofService повторяет условие ServiceID из SQL-реализации: подписка связана с сервисом
+// или не связана ни с одним, но её название — один из псевдонимов сервиса.
+// Вызывающий должен держать блокировку.
+func (r *memoryRepo) ofService(sub model.Subscription, serviceID uuid.UUID) bool {
+	if sub.ServiceID != nil {
+		return *sub.ServiceID == serviceID
+	}
+	svc, ok := r.services[serviceID]
+	return ok && slices.Contains(svc.Aliases, model.NormalizeLabel(sub.ServiceName))
+}
+
+// live возвращает подписку, если она существует и не удалена мягко.
+// Вызывающий должен держать блокировку.
+func (r *memoryRepo) live(id uuid.UUID) (memoryEntry, bool) {
+	e, ok := r.subs[id]
+	if !ok || e.sub.DeletedAt.Valid {
+		return memoryEntry{}, false
+	}
+	return e, true
+}
+
+// activeIn повторяет условие ActiveIn из SQL-реализации: подписка действует
+// в месяце month и не приостановлена на его начало.
+func (r *memoryRepo) activeIn(sub model.Subscription, month time.Time) bool {
+	if !sub.StartDate.Before(month.AddDate(0, 1, 0)) {
+		return false
+	}
+	if sub.EndDate != nil && sub.EndDate.Before(month) {
+		return false
+	}
+	return !model.PausedAt(r.pauses[sub.ID], month)
+}
+
+// monthOf возвращает первое число месяца даты t, как date_trunc('month', t).
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func cloneSubscription(sub model.Subscription) model.Subscription {
+	if sub.EndDate != nil {
+		end := *sub.EndDate
+		sub.EndDate = &end
+	}
+	if sub.TrialEndDate != nil {
+		trialEnd := *sub.TrialEndDate
+		sub.TrialEndDate = &trialEnd
+	}
+	sub.Tags = slices.Clone(sub.Tags)
+	if sub.ServiceID != nil {
+		serviceID := *sub.ServiceID
+		sub.ServiceID = &serviceID
+	}
+	if sub.CanceledAt != nil {
+		canceledAt := *sub.CanceledAt
+		sub.CanceledAt = &canceledAt
+	}
+	return sub
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *memoryRepo) CreateAuditRecord(ctx context.Context, rec *model.AuditRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rec.ID == uuid.Nil {
+		rec.ID = uuid.New()
+	}
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now()
+	}
+	stored := *rec
+	stored.Changes = maps.Clone(rec.Changes)
+	r.audit = append(r.audit, stored)
+	return nil
+}
+
+func (r *memoryRepo) ListAuditRecords(ctx context.Context, filter model.AuditFilter, limit, offset int) (*model.AuditList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []model.AuditRecord
+	for _, rec := range r.audit {
+		if auditMatches(rec, filter) {
+			matched = append(matched, rec)
+		}
+	}
+	total := int64(len(matched))
+
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	matched = matched[offset:]
+	if limit >= 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+
+	items := make([]model.AuditRecord, 0, len(matched))
+	for _, rec := range matched {
+		rec.Changes = maps.Clone(rec.Changes)
+		items = append(items, rec)
+	}
+	return &model.AuditList{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+// auditMatches повторяет условия applyAuditFilter.
+func auditMatches(rec model.AuditRecord, filter model.AuditFilter) bool {
+	switch {
+	case filter.SubscriptionID != nil && rec.SubscriptionID != *filter.SubscriptionID:
+		return false
+	case filter.UserID != nil && rec.UserID != *filter.UserID:
+		return false
+	case filter.Actor != nil && rec.Actor != *filter.Actor:
+		return false
+	case filter.Operation != nil && rec.Operation != *filter.Operation:
+		return false
+	case filter.Since != nil && rec.CreatedAt.Before(*filter.Since):
+		return false
+	case filter.Until != nil && !rec.CreatedAt.Before(*filter.Until):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *memoryRepo) CreateBudget(ctx context.Context, b *model.Budget) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	if _, ok := r.budgets[b.ID]; ok || r.budgetTaken(*b) {
+		return ErrConflict
+	}
+	now := time.Now()
+	b.CreatedAt, b.UpdatedAt = now, now
+	r.budgets[b.ID] = *b
+	return nil
+}
+
+func (r *memoryRepo) GetBudget(ctx context.Context, id uuid.UUID) (*model.Budget, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	b, ok := r.budgets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &b, nil
+}
+
+func (r *memoryRepo) UpdateBudget(ctx context.Context, b *model.Budget) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.budgets[b.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.budgetTaken(*b) {
+		return ErrConflict
+	}
+	b.CreatedAt, b.UpdatedAt = existing.CreatedAt, time.Now()
+	r.budgets[b.ID] = *b
+	return nil
+}
+
+func (r *memoryRepo) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.budgets[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.budgets, id)
+	return nil
+}
+
+func (r *memoryRepo) ListBudgets(ctx context.Context, userID *uuid.UUID) ([]model.Budget, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budgets := []model.Budget{}
+	for _, b := range r.budgets {
+		if userID != nil && b.UserID != *userID {
+			continue
+		}
+		budgets = append(budgets, b)
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		if budgets[i].UserID != budgets[j].UserID {
+			return budgets[i].UserID.String() < budgets[j].UserID.String()
+		}
+		return budgets[i].Category < budgets[j].Category
+	})
+	return budgets, nil
+}
+
+// budgetTaken повторяет уникальный индекс idx_budgets_user_category: у пользователя
+// не может быть другого бюджета с той же категорией.
+// Вызывающий должен держать блокировку.
+func (r *memoryRepo) budgetTaken(b model.Budget) bool {
+	for id, existing := range r.budgets {
+		if id != b.ID && existing.UserID == b.UserID && existing.Category == b.Category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *memoryRepo) CreatePause(ctx context.Context, p *model.Pause) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.live(p.SubscriptionID); !ok {
+		return ErrNotFound
+	}
+	pauses := r.pauses[p.SubscriptionID]
+	for _, existing := range pauses {
+		if existing.ResumedAt == nil {
+			return ErrConflict
+		}
+	}
+
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	now := time.Now()
+	p.CreatedAt, p.UpdatedAt = now, now
+	pauses = append(pauses, clonePause(*p))
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i].PausedFrom.Before(pauses[j].PausedFrom) })
+	r.pauses[p.SubscriptionID] = pauses
+	return nil
+}
+
+func (r *memoryRepo) UpdatePause(ctx context.Context, p *model.Pause) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pauses := r.pauses[p.SubscriptionID]
+	for i := range pauses {
+		if pauses[i].ID == p.ID {
+			p.UpdatedAt = time.Now()
+			pauses[i].ResumedAt = clonePause(*p).ResumedAt
+			pauses[i].UpdatedAt = p.UpdatedAt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryRepo) ListPauses(ctx context.Context, subscriptionID uuid.UUID) ([]model.Pause, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pauses := make([]model.Pause, 0, len(r.pauses[subscriptionID]))
+	for _, p := range r.pauses[subscriptionID] {
+		pauses = append(pauses, clonePause(p))
+	}
+	return pauses, nil
+}
+
+func clonePause(p model.Pause) model.Pause {
+	if p.ResumedAt != nil {
+		resumed := *p.ResumedAt
+		p.ResumedAt = &resumed
+	}
+	return p
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *memoryRepo) CreatePriceChange(ctx context.Context, pc *model.PriceChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.live(pc.SubscriptionID); !ok {
+		return ErrNotFound
+	}
+	changes := r.prices[pc.SubscriptionID]
+	for _, existing := range changes {
+		if existing.EffectiveFrom.Equal(pc.EffectiveFrom) {
+			return ErrConflict
+		}
+	}
+
+	if pc.ID == uuid.Nil {
+		pc.ID = uuid.New()
+	}
+	if pc.CreatedAt.IsZero() {
+		pc.CreatedAt = time.Now()
+	}
+	changes = append(changes, *pc)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].EffectiveFrom.Before(changes[j].EffectiveFrom) })
+	r.prices[pc.SubscriptionID] = changes
+	return nil
+}
+
+func (r *memoryRepo) ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]model.PriceChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]model.PriceChange{}, r.prices[subscriptionID]...), nil
+}
+
+func (r *memoryRepo) DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := r.prices[subscriptionID]
+	for i, pc := range changes {
+		if pc.ID == id {
+			r.prices[subscriptionID] = append(changes[:i:i], changes[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
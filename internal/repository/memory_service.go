@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *memoryRepo) CreateService(ctx context.Context, svc *model.Service) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if svc.ID == uuid.Nil {
+		svc.ID = uuid.New()
+	}
+	if _, ok := r.services[svc.ID]; ok || r.aliasesTaken(*svc) {
+		return ErrConflict
+	}
+	now := time.Now()
+	svc.CreatedAt, svc.UpdatedAt = now, now
+	r.services[svc.ID] = cloneService(*svc)
+	return nil
+}
+
+func (r *memoryRepo) GetService(ctx context.Context, id uuid.UUID) (*model.Service, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	svc, ok := r.services[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	svc = cloneService(svc)
+	return &svc, nil
+}
+
+func (r *memoryRepo) UpdateService(ctx context.Context, svc *model.Service) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.services[svc.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.aliasesTaken(*svc) {
+		return ErrConflict
+	}
+	svc.CreatedAt, svc.UpdatedAt = existing.CreatedAt, time.Now()
+	r.services[svc.ID] = cloneService(*svc)
+	return nil
+}
+
+func (r *memoryRepo) DeleteService(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.services[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.services, id)
+	// Как ON DELETE SET NULL у subscriptions.service_id
+	for subID, e := range r.subs {
+		if e.sub.ServiceID != nil && *e.sub.ServiceID == id {
+			e.sub.ServiceID = nil
+			r.subs[subID] = e
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepo) ListServices(ctx context.Context) ([]model.Service, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	services := make([]model.Service, 0, len(r.services))
+	for _, svc := range r.services {
+		services = append(services, cloneService(svc))
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+func (r *memoryRepo) FindServiceByAlias(ctx context.Context, alias string) (*model.Service, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, svc := range r.services {
+		if slices.Contains(svc.Aliases, alias) {
+			svc = cloneService(svc)
+			return &svc, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// aliasesTaken повторяет первичный ключ service_aliases: псевдоним не может
+// принадлежать другому сервису. Вызывающий должен держать блокировку.
+func (r *memoryRepo) aliasesTaken(svc model.Service) bool {
+	for id, existing := range r.services {
+		if id == svc.ID {
+			continue
+		}
+		for _, alias := range svc.Aliases {
+			if slices.Contains(existing.Aliases, alias) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func cloneService(svc model.Service) model.Service {
+	svc.Aliases = slices.Clone(svc.Aliases)
+	return svc
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// noLock блокировка репозитория внутри транзакции: её владелец уже держит настоящую.
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
+
+// Transaction держит эксклюзивную блокировку на всё время fn, поэтому транзакции
+// выполняются по одной и не видят чужих незавершённых изменений. При ошибке
+// состояние восстанавливается из снимка. Вложенная транзакция ведёт себя как точка
+// сохранения. fn не должна обращаться к исходному репозиторию — это взаимоблокировка.
+func (r *memoryRepo) Transaction(ctx context.Context, fn func(tx Repository) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := r.snapshot()
+	if err := fn(&memoryRepo{mu: noLock{}, memoryState: r.memoryState}); err != nil {
+		*r.memoryState = snapshot
+		return err
+	}
+	return nil
+}
+
+// LockUser ничего не делает: транзакции в памяти и так выполняются по одной.
+func (r *memoryRepo) LockUser(ctx context.Context, _ uuid.UUID) error {
+	return ctx.Err()
+}
+
+// snapshot копирует состояние настолько глубоко, насколько его меняют методы репозитория:
+// записи в map заменяются целиком, а срезы цен и пауз правятся на месте.
+func (s *memoryState) snapshot() memoryState {
+	cp := memoryState{
+		seq:      s.seq,
+		subs:     maps.Clone(s.subs),
+		prices:   maps.Clone(s.prices),
+		pauses:   maps.Clone(s.pauses),
+		budgets:  maps.Clone(s.budgets),
+		services: maps.Clone(s.services),
+		audit:    slices.Clone(s.audit),
+	}
+	for id, changes := range cp.prices {
+		cp.prices[id] = slices.Clone(changes)
+	}
+	for id, pauses := range cp.pauses {
+		cp.pauses[id] = slices.Clone(pauses)
+	}
+	return cp
+}
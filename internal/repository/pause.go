@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *repo) CreatePause(ctx context.Context, p *model.Pause) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	// Уникальный индекс по открытым паузам не даёт приостановить подписку дважды
+	return mapErr(db.Omit("Subscription").Create(p).Error)
+}
+
+func (r *repo) UpdatePause(ctx context.Context, p *model.Pause) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Model(p).Omit("Subscription").Update("resumed_at", p.ResumedAt)
+	if res.Error != nil {
+		return mapErr(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) ListPauses(ctx context.Context, subscriptionID uuid.UUID) ([]model.Pause, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	pauses := []model.Pause{}
+	if err := db.Where("subscription_id = ?", subscriptionID).
+		Order("paused_from").
+		Find(&pauses).Error; err != nil {
+		return nil, err
+	}
+	return pauses, nil
+}
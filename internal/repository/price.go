@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+func (r *repo) CreatePriceChange(ctx context.Context, pc *model.PriceChange) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if pc.ID == uuid.Nil {
+		pc.ID = uuid.New()
+	}
+	return mapErr(db.Omit("Subscription").Create(pc).Error)
+}
+
+func (r *repo) ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]model.PriceChange, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	changes := []model.PriceChange{}
+	if err := db.Where("subscription_id = ?", subscriptionID).
+		Order("effective_from").
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *repo) DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Delete(&model.PriceChange{}, "id = ? AND subscription_id = ?", id, subscriptionID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
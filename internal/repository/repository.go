@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"log"
@@ -10,6 +12,7 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func InitDB(cfg *config.Config) (*gorm.DB, error) {
@@ -18,12 +21,12 @@ func InitDB(cfg *config.Config) (*gorm.DB, error) {
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPass, cfg.DBName,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := db.AutoMigrate(&model.Subscription{}); err != nil {
+	if err := db.AutoMigrate(&model.Service{}, &model.ServiceAlias{}, &model.Subscription{}, &model.PriceChange{}, &model.Pause{}, &model.Budget{}, &model.Tag{}, &model.SubscriptionTag{}, &model.AuditRecord{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate: %w", err)
 	}
 
@@ -31,56 +34,326 @@ func InitDB(cfg *config.Config) (*gorm.DB, error) {
 }
 
 type Repository interface {
-	Create(sub *model.Subscription) error
-	GetByID(id uuid.UUID) (*model.Subscription, error)
-	Update(sub *model.Subscription) error
-	Delete(id uuid.UUID) error
-	List(userID *uuid.UUID, serviceName *string, limit, offset int) (*model.SubscriptionList, error)
-	CalculateTotal(userID *uuid.UUID, serviceName *string, from, to time.Time) (int, error)
+	// Transaction выполняет fn в транзакции: изменения, сделанные через tx, фиксируются,
+	// только если fn вернула nil. Транзакции можно вкладывать друг в друга
+	Transaction(ctx context.Context, fn func(tx Repository) error) error
+	// LockUser блокирует создание подписок пользователя другими транзакциями до конца текущей.
+	// Вне транзакции блокировка снимается сразу же
+	LockUser(ctx context.Context, userID uuid.UUID) error
+
+	Create(ctx context.Context, sub *model.Subscription) error
+	// CreateBatch сохраняет подписки многострочными INSERT по createBatchSize строк в одной транзакции
+	CreateBatch(ctx context.Context, subs []*model.Subscription) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	// GetDeleted возвращает мягко удалённую подписку. ErrNotFound, если такой нет
+	GetDeleted(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	// Update заменяет поля подписки, кроме данных об отмене, если её версия в хранилище
+	// равна sub.Version, и увеличивает sub.Version. ErrVersionMismatch, если версия другая
+	Update(ctx context.Context, sub *model.Subscription) error
+	// Cancel сохраняет EndDate, CanceledAt и CancelReason подписки с той же проверкой версии, что и Update
+	Cancel(ctx context.Context, sub *model.Subscription) error
+	// Delete мягко удаляет подписку, проставляя deleted_at
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Restore снимает мягкое удаление и увеличивает версию. ErrNotFound, если удалённой подписки с таким id нет
+	Restore(ctx context.Context, id uuid.UUID) error
+	// PurgeDeleted окончательно удаляет подписки, мягко удалённые раньше before,
+	// вместе с их историей и возвращает удалённые подписки
+	PurgeDeleted(ctx context.Context, before time.Time) ([]model.Subscription, error)
+	List(ctx context.Context, filter model.SubscriptionFilter, limit, offset int) (*model.SubscriptionList, error)
+	// ListInBatches передаёт в fn все подписки, подходящие под фильтр, порциями по batchSize,
+	// читая каждую порцию отдельным запросом с курсором. Ошибка fn прекращает чтение
+	ListInBatches(ctx context.Context, filter model.SubscriptionFilter, batchSize int, fn func(batch []model.Subscription) error) error
+	// CalculateTotal возвращает сумму списаний по каждой валюте в минимальных единицах
+	CalculateTotal(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) (map[string]int, error)
+	// CalculateTotalByCategory возвращает суммы CalculateTotal по категориям и валютам,
+	// упорядоченные по категории
+	CalculateTotalByCategory(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) ([]model.CategoryAmount, error)
+	// CalculateBreakdown возвращает суммы списаний за месяцы [from, to] по месяцам,
+	// сервисам и валютам, упорядоченные по месяцу и названию сервиса
+	CalculateBreakdown(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) ([]model.BreakdownItem, error)
+	// ForecastByMonth возвращает суммы ожидаемых списаний в полуинтервале [from, until)
+	// по месяцам и валютам, включая будущие списания бессрочных подписок
+	ForecastByMonth(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.MonthlyAmount, error)
+	// ListCharges возвращает списания в полуинтервале [from, until) по дате,
+	// включая будущие списания бессрочных подписок
+	ListCharges(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.Charge, error)
+	// ListTrialsEnding возвращает подписки, у которых пробный период заканчивается
+	// в полуинтервале [from, until) и за ним последует платное списание
+	ListTrialsEnding(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.Subscription, error)
+	// CountCancelReasons считает отменённые подписки по причинам, с отменой в полуинтервале
+	// [from, until); nil-границы не ограничивают выборку. Упорядочено по убыванию числа
+	CountCancelReasons(ctx context.Context, filter model.SubscriptionFilter, from, until *time.Time) ([]model.ReasonCount, error)
+
+	CreatePriceChange(ctx context.Context, pc *model.PriceChange) error
+	// ListPriceChanges возвращает изменения цены подписки по возрастанию EffectiveFrom
+	ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]model.PriceChange, error)
+	DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error
+
+	CreatePause(ctx context.Context, p *model.Pause) error
+	// UpdatePause сохраняет дату возобновления паузы
+	UpdatePause(ctx context.Context, p *model.Pause) error
+	// ListPauses возвращает паузы подписки по возрастанию PausedFrom
+	ListPauses(ctx context.Context, subscriptionID uuid.UUID) ([]model.Pause, error)
+
+	CreateBudget(ctx context.Context, b *model.Budget) error
+	GetBudget(ctx context.Context, id uuid.UUID) (*model.Budget, error)
+	UpdateBudget(ctx context.Context, b *model.Budget) error
+	DeleteBudget(ctx context.Context, id uuid.UUID) error
+	// ListBudgets возвращает бюджеты пользователя (или всех, если userID == nil):
+	// сначала общий, затем по категориям
+	ListBudgets(ctx context.Context, userID *uuid.UUID) ([]model.Budget, error)
+
+	// CreateAuditRecord добавляет запись в журнал изменений
+	CreateAuditRecord(ctx context.Context, rec *model.AuditRecord) error
+	// ListAuditRecords возвращает записи журнала по возрастанию времени
+	ListAuditRecords(ctx context.Context, filter model.AuditFilter, limit, offset int) (*model.AuditList, error)
+
+	// CreateService сохраняет запись каталога вместе с псевдонимами. Псевдоним,
+	// занятый другим сервисом, приводит к ErrConflict
+	CreateService(ctx context.Context, svc *model.Service) error
+	GetService(ctx context.Context, id uuid.UUID) (*model.Service, error)
+	// UpdateService заменяет поля и псевдонимы записи каталога
+	UpdateService(ctx context.Context, svc *model.Service) error
+	DeleteService(ctx context.Context, id uuid.UUID) error
+	// ListServices возвращает каталог по алфавиту
+	ListServices(ctx context.Context) ([]model.Service, error)
+	// FindServiceByAlias ищет сервис по нормализованному псевдониму
+	FindServiceByAlias(ctx context.Context, alias string) (*model.Service, error)
 }
 
+// createBatchSize число строк в одном INSERT при пакетном создании подписок
+const createBatchSize = 100
+
 type repo struct {
-	db *gorm.DB
+	db           *gorm.DB
+	queryTimeout time.Duration
 }
 
-func NewRepository(db *gorm.DB) Repository {
-	return &repo{db: db}
+// NewRepository создаёт репозиторий поверх GORM. Если queryTimeout > 0,
+// каждый запрос ограничивается этим временем поверх дедлайна вызывающего.
+func NewRepository(db *gorm.DB, queryTimeout time.Duration) Repository {
+	return &repo{db: db, queryTimeout: queryTimeout}
+}
+
+// withContext возвращает сессию GORM, привязанную к контексту запроса с учётом таймаута.
+func (r *repo) withContext(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	if r.queryTimeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+		return r.db.WithContext(ctx), cancel
+	}
+	return r.db.WithContext(ctx), func() {}
+}
+
+func (r *repo) Transaction(ctx context.Context, fn func(tx Repository) error) error {
+	// Таймаут ограничивает каждый запрос транзакции, а не её целиком
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repo{db: tx, queryTimeout: r.queryTimeout})
+	})
+}
+
+func (r *repo) LockUser(ctx context.Context, userID uuid.UUID) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// Транзакционная advisory-блокировка освобождается при фиксации или откате
+	return mapErr(db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", userID.String()).Error)
 }
 
-func (r *repo) Create(sub *model.Subscription) error {
+func (r *repo) Create(ctx context.Context, sub *model.Subscription) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	if sub.ID == uuid.Nil {
 		sub.ID = uuid.New()
 	}
+	sub.Version = 1
 	log.Printf("Creating subscription with ID: %s", sub.ID.String())
-	return r.db.Create(sub).Error
+	return mapErr(db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(sub).Error; err != nil {
+			return err
+		}
+		return setTags(tx, sub.ID, sub.Tags)
+	}))
+}
+
+func (r *repo) CreateBatch(ctx context.Context, subs []*model.Subscription) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	for _, sub := range subs {
+		if sub.ID == uuid.Nil {
+			sub.ID = uuid.New()
+		}
+		sub.Version = 1
+	}
+	return mapErr(db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(subs, createBatchSize).Error; err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			if len(sub.Tags) == 0 {
+				continue
+			}
+			if err := setTags(tx, sub.ID, sub.Tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
 }
 
-func (r *repo) GetByID(id uuid.UUID) (*model.Subscription, error) {
+func (r *repo) GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var sub model.Subscription
-	if err := r.db.First(&sub, "id = ?", id).Error; err != nil {
+	if err := db.First(&sub, "id = ?", id).Error; err != nil {
+		return nil, mapErr(err)
+	}
+	subs := []model.Subscription{sub}
+	if err := loadTags(db, subs); err != nil {
 		return nil, err
 	}
-	return &sub, nil
+	return &subs[0], nil
 }
 
-func (r *repo) Update(sub *model.Subscription) error {
-	return r.db.Save(sub).Error
+func (r *repo) GetDeleted(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var sub model.Subscription
+	if err := db.Unscoped().First(&sub, "id = ? AND deleted_at IS NOT NULL", id).Error; err != nil {
+		return nil, mapErr(err)
+	}
+	subs := []model.Subscription{sub}
+	if err := loadTags(db, subs); err != nil {
+		return nil, err
+	}
+	return &subs[0], nil
 }
 
-func (r *repo) Delete(id uuid.UUID) error {
-	return r.db.Delete(&model.Subscription{}, "id = ?", id).Error
+func (r *repo) Update(ctx context.Context, sub *model.Subscription) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	version := sub.Version
+	sub.Version++
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Save вставил бы отсутствующую запись, поэтому обновляем явно по id,
+		// а проверка версии в том же UPDATE не даёт затереть чужое изменение
+		res := tx.Model(sub).Where("version = ?", version).
+			Select("*").Omit("id", "canceled_at", "cancel_reason").Updates(sub)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return staleOrMissing(tx, sub.ID)
+		}
+		return setTags(tx, sub.ID, sub.Tags)
+	})
+	if err != nil {
+		sub.Version = version
+	}
+	return mapErr(err)
 }
 
-func (r *repo) List(userID *uuid.UUID, serviceName *string, limit, offset int) (*model.SubscriptionList, error) {
-	var subs []model.Subscription
-	baseQuery := r.db.Model(&model.Subscription{})
+func (r *repo) Cancel(ctx context.Context, sub *model.Subscription) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	version := sub.Version
+	sub.Version++
+	res := db.Model(sub).Where("version = ?", version).
+		Select("end_date", "canceled_at", "cancel_reason", "version").Updates(sub)
+	err := res.Error
+	if err == nil && res.RowsAffected == 0 {
+		err = staleOrMissing(db, sub.ID)
+	}
+	if err != nil {
+		sub.Version = version
+	}
+	return mapErr(err)
+}
 
-	if userID != nil {
-		baseQuery = baseQuery.Where("user_id = ?", *userID)
+// staleOrMissing объясняет, почему UPDATE с проверкой версии не затронул ни одной строки.
+func staleOrMissing(db *gorm.DB, id uuid.UUID) error {
+	var n int64
+	if err := db.Model(&model.Subscription{}).Where("id = ?", id).Count(&n).Error; err != nil {
+		return err
 	}
-	if serviceName != nil {
-		baseQuery = baseQuery.Where("service_name = ?", *serviceName)
+	if n == 0 {
+		return ErrNotFound
 	}
+	return ErrVersionMismatch
+}
+
+func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Delete(&model.Subscription{}, "id = ?", id)
+	if res.Error != nil {
+		return mapErr(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) Restore(ctx context.Context, id uuid.UUID) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Unscoped().Model(&model.Subscription{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if res.Error != nil {
+		return mapErr(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) PurgeDeleted(ctx context.Context, before time.Time) ([]model.Subscription, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var purged []model.Subscription
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Блокировка не даёт восстановить подписку между выборкой и удалением
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at < ?", before).
+			Find(&purged).Error; err != nil {
+			return err
+		}
+		if len(purged) == 0 {
+			return nil
+		}
+		if err := loadTags(tx, purged); err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, len(purged))
+		for i := range purged {
+			ids[i] = purged[i].ID
+		}
+		// Цены, паузы и метки удаляются каскадно по внешним ключам
+		return tx.Unscoped().Delete(&model.Subscription{}, "id IN ?", ids).Error
+	})
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return purged, nil
+}
+
+func (r *repo) List(ctx context.Context, filter model.SubscriptionFilter, limit, offset int) (*model.SubscriptionList, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var subs []model.Subscription
+	baseQuery := applyFilter(db.Table("subscriptions AS s"), filter)
 
 	// Получаем total
 	var total int64
@@ -95,6 +368,9 @@ func (r *repo) List(userID *uuid.UUID, serviceName *string, limit, offset int) (
 		Find(&subs).Error; err != nil {
 		return nil, err
 	}
+	if err := loadTags(db, subs); err != nil {
+		return nil, err
+	}
 
 	return &model.SubscriptionList{
 		Total: total,
@@ -102,30 +378,225 @@ func (r *repo) List(userID *uuid.UUID, serviceName *string, limit, offset int) (
 	}, nil
 }
 
-func (r *repo) CalculateTotal(userID *uuid.UUID, serviceName *string, from, to time.Time) (int, error) {
-	var total int
+func (r *repo) ListInBatches(ctx context.Context, filter model.SubscriptionFilter, batchSize int, fn func(batch []model.Subscription) error) error {
+	// Курсор по id вместо OFFSET: каждая порция читается одинаково быстро
+	// и не смещается, если подписки добавляют во время чтения
+	var after uuid.UUID
+	for {
+		batch, err := r.listAfter(ctx, filter, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].ID
+	}
+}
 
-	// считаем количество месяцев пересечения и умножаем на price
-	// Используем GREATEST/LEAST для выбора пересекающегося диапазона
-	// CASE WHEN months < 1 THEN 1 ELSE months END — чтобы минимальный период был 1 месяц
-	query := r.db.Model(&model.Subscription{}).
-		Select(`
-			COALESCE(SUM(price * GREATEST(1, DATE_PART('month', AGE(LEAST(COALESCE(end_date, NOW()), ?), GREATEST(start_date, ?))))), 0) as total
-		`, to, from)
+// listAfter возвращает до limit подписок с id больше after по возрастанию id.
+func (r *repo) listAfter(ctx context.Context, filter model.SubscriptionFilter, after uuid.UUID, limit int) ([]model.Subscription, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
 
-	if userID != nil {
-		query = query.Where("user_id = ?", *userID)
+	var subs []model.Subscription
+	if err := applyFilter(db.Table("subscriptions AS s"), filter).
+		Where("s.id > ?", after).
+		Order("s.id").
+		Limit(limit).
+		Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	if err := loadTags(db, subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *repo) CalculateTotal(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) (map[string]int, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// Суммируем фактические списания в месяцах [from, to]. Бессрочные подписки,
+	// как и раньше, учитываются только до текущего момента.
+	query := applyFilter(chargesQuery(db, from, to.AddDate(0, 1, 0), time.Now()), filter).
+		Select("s.currency, SUM(" + chargeAmountSQL + ") AS total").
+		Group("s.currency")
+
+	var rows []struct {
+		Currency string
+		Total    int
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = row.Total
+	}
+	return totals, nil
+}
+
+func (r *repo) CalculateTotalByCategory(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) ([]model.CategoryAmount, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	amounts := []model.CategoryAmount{}
+	if err := applyFilter(chargesQuery(db, from, to.AddDate(0, 1, 0), time.Now()), filter).
+		Select("s.category, SUM(" + chargeAmountSQL + ") AS amount, s.currency").
+		Group("s.category, s.currency").
+		Order("s.category, s.currency").
+		Scan(&amounts).Error; err != nil {
+		return nil, err
+	}
+	return amounts, nil
+}
+
+func (r *repo) CalculateBreakdown(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time) ([]model.BreakdownItem, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// Та же выборка списаний, что и в CalculateTotal, сгруппированная по месяцам
+	items := []model.BreakdownItem{}
+	if err := applyFilter(chargesQuery(db, from, to.AddDate(0, 1, 0), time.Now()), filter).
+		Select("date_trunc('month', c.charged_at) AS month, s.service_name, SUM(" + chargeAmountSQL + ") AS amount, s.currency").
+		Group("1, s.service_name, s.currency").
+		Order("1, s.service_name, s.currency").
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *repo) ForecastByMonth(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.MonthlyAmount, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// В отличие от CalculateTotal бессрочные подписки не обрезаются текущим моментом
+	amounts := []model.MonthlyAmount{}
+	if err := applyFilter(chargesQuery(db, from, until, until), filter).
+		Select("date_trunc('month', c.charged_at) AS month, SUM(" + chargeAmountSQL + ") AS amount, s.currency").
+		Group("1, s.currency").
+		Order("1, s.currency").
+		Scan(&amounts).Error; err != nil {
+		return nil, err
 	}
-	if serviceName != nil {
-		query = query.Where("service_name = ?", *serviceName)
+	return amounts, nil
+}
+
+func (r *repo) ListCharges(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.Charge, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	charges := []model.Charge{}
+	if err := applyFilter(chargesQuery(db, from, until, until), filter).
+		Select("s.id AS subscription_id, s.service_name, c.charged_at, " + chargeAmountSQL + " AS amount, s.currency").
+		Order("c.charged_at, s.service_name").
+		Scan(&charges).Error; err != nil {
+		return nil, err
 	}
+	return charges, nil
+}
 
-	// Пересечение диапазонов
-	query = query.Where("(start_date <= ?) AND (COALESCE(end_date, NOW()) >= ?)", to, from)
+func (r *repo) ListTrialsEnding(ctx context.Context, filter model.SubscriptionFilter, from, until time.Time) ([]model.Subscription, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
 
-	if err := query.Pluck("total", &total).Error; err != nil {
-		return 0, err
+	subs := []model.Subscription{}
+	if err := applyFilter(db.Table("subscriptions AS s"), filter).
+		Where("s.trial_end_date >= ? AND s.trial_end_date < ?", from, until).
+		Where("s.end_date IS NULL OR s.end_date + INTERVAL '1 month' > s.trial_end_date").
+		Order("s.trial_end_date").
+		Find(&subs).Error; err != nil {
+		return nil, err
 	}
+	if err := loadTags(db, subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *repo) CountCancelReasons(ctx context.Context, filter model.SubscriptionFilter, from, until *time.Time) ([]model.ReasonCount, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
 
-	return total, nil
+	q := applyFilter(db.Table("subscriptions AS s"), filter).Where("s.canceled_at IS NOT NULL")
+	if from != nil {
+		q = q.Where("s.canceled_at >= ?", *from)
+	}
+	if until != nil {
+		q = q.Where("s.canceled_at < ?", *until)
+	}
+	counts := []model.ReasonCount{}
+	if err := q.Select("s.cancel_reason AS reason, COUNT(*) AS count").
+		Group("s.cancel_reason").
+		Order("count DESC, reason").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// normalizedServiceName название подписки в том же виде, что model.NormalizeLabel:
+// любые пробельные символы схлопываются в один пробел, затем крайние пробелы
+// убираются, и название приводится к нижнему регистру.
+const normalizedServiceName = `lower(btrim(regexp_replace(s.service_name, '\s+', ' ', 'g')))`
+
+// applyFilter добавляет условия фильтра к запросу по таблице subscriptions AS s.
+// Мягкое удаление учитывается явно: автоматическое условие GORM не знает о псевдониме
+// и не добавляется в Count и Scan.
+func applyFilter(q *gorm.DB, filter model.SubscriptionFilter) *gorm.DB {
+	q = q.Unscoped()
+	if !filter.IncludeDeleted {
+		q = q.Where("s.deleted_at IS NULL")
+	}
+	if filter.UserID != nil {
+		q = q.Where("s.user_id = ?", *filter.UserID)
+	}
+	if filter.ServiceName != nil {
+		q = q.Where(normalizedServiceName+" = ?", *filter.ServiceName)
+	}
+	if filter.ServiceID != nil {
+		q = q.Where(`(s.service_id = ? OR (s.service_id IS NULL AND `+normalizedServiceName+` IN (
+			SELECT sa.alias FROM service_aliases sa WHERE sa.service_id = ?
+		)))`, *filter.ServiceID, *filter.ServiceID)
+	}
+	if filter.Category != nil {
+		q = q.Where("s.category = ?", *filter.Category)
+	}
+	if filter.Tag != nil {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+			WHERE st.subscription_id = s.id AND t.name = ?
+		)`, *filter.Tag)
+	}
+	if filter.ActiveIn != nil {
+		month := *filter.ActiveIn
+		q = q.Where("s.start_date < ? AND (s.end_date IS NULL OR s.end_date >= ?)", month.AddDate(0, 1, 0), month).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM pauses p
+				WHERE p.subscription_id = s.id AND p.paused_from <= ? AND (p.resumed_at IS NULL OR p.resumed_at > ?)
+			)`, month, month)
+	}
+	return q
+}
+
+// mapErr приводит ошибки GORM к ошибкам пакета repository. Нарушение внешнего
+// ключа означает, что родительской подписки нет.
+func mapErr(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	default:
+		return err
+	}
 }
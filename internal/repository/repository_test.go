@@ -0,0 +1,949 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/config"
+	"subscriptions/internal/model"
+)
+
+// testRepos возвращает реализации, на которых прогоняются общие тесты: всегда память
+// и PostgreSQL, если задана TEST_DB_NAME. Остальные параметры подключения берутся
+// из DB_HOST, DB_PORT, DB_USER и DB_PASSWORD. Таблицы тестовой базы очищаются,
+// поэтому она должна быть отдельной; расширение uuid-ossp в ней должно быть установлено.
+func testRepos(t *testing.T) map[string]func(t *testing.T) Repository {
+	repos := map[string]func(t *testing.T) Repository{
+		"memory": func(*testing.T) Repository { return NewMemoryRepository() },
+	}
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		return repos
+	}
+
+	db, err := InitDB(&config.Config{
+		DBHost: os.Getenv("DB_HOST"),
+		DBPort: os.Getenv("DB_PORT"),
+		DBUser: os.Getenv("DB_USER"),
+		DBPass: os.Getenv("DB_PASSWORD"),
+		DBName: name,
+	})
+	require.NoError(t, err)
+	repos["postgres"] = func(t *testing.T) Repository {
+		require.NoError(t, db.Exec(`TRUNCATE subscriptions, price_changes, pauses, budgets, tags, subscription_tags, services, service_aliases, audit_log CASCADE`).Error)
+		return NewRepository(db, 0)
+	}
+	return repos
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var (
+	testUser  = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	otherUser = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+)
+
+// seed сохраняет подписки, на которых проверяются выборки и суммы. Все даты в прошлом,
+// поэтому суммы по бессрочным подпискам не зависят от текущего времени.
+func seed(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	monthly := func(name string, price int, userID uuid.UUID, start time.Time) *model.Subscription {
+		return &model.Subscription{
+			ServiceName: name, Price: price, Currency: "RUB", UserID: userID, StartDate: start,
+			BillingPeriod: model.BillingMonth, BillingInterval: 1,
+		}
+	}
+
+	ended := monthly("Spotify", 500, testUser, month(2024, 3))
+	ended.EndDate = ptr(month(2024, 5))
+
+	yearly := monthly("Domain", 1200, testUser, month(2023, 6))
+	yearly.BillingPeriod = model.BillingYear
+	yearly.Currency = "USD"
+
+	subs := []*model.Subscription{
+		monthly("Netflix", 1000, testUser, month(2024, 1)),
+		ended,
+		monthly("Yandex Plus", 300, testUser, month(2024, 8)),
+		monthly("Netflix", 1000, otherUser, month(2024, 1)),
+		yearly,
+	}
+	for _, sub := range subs {
+		require.NoError(t, repo.Create(ctx, sub))
+	}
+}
+
+func TestCalculateTotal(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      *uuid.UUID
+		serviceName *string
+		from, to    time.Time
+		want        map[string]int
+	}{
+		{
+			// Месяцы from и to включаются; Spotify списывается трижды, Yandex Plus ещё не началась
+			name:   "user",
+			userID: &testUser,
+			from:   month(2024, 2),
+			to:     month(2024, 5),
+			want:   map[string]int{"RUB": 1000*4 + 500*3},
+		},
+		{
+			name:        "service",
+			serviceName: ptr("netflix"),
+			from:        month(2024, 1),
+			to:          month(2024, 4),
+			want:        map[string]int{"RUB": 1000*4 + 1000*4},
+		},
+		{
+			name:   "single month",
+			userID: &testUser,
+			from:   month(2024, 9),
+			to:     month(2024, 9),
+			want:   map[string]int{"RUB": 1000 + 300},
+		},
+		{
+			name:   "window longer than a year",
+			userID: &otherUser,
+			from:   month(2024, 1),
+			to:     month(2025, 3),
+			want:   map[string]int{"RUB": 1000 * 15},
+		},
+		{
+			name:        "yearly",
+			serviceName: ptr("domain"),
+			from:        month(2023, 1),
+			to:          month(2025, 12),
+			want:        map[string]int{"USD": 1200 * 3},
+		},
+		{
+			name:   "currencies are summed separately",
+			userID: &testUser,
+			from:   month(2024, 6),
+			to:     month(2024, 6),
+			want:   map[string]int{"RUB": 1000, "USD": 1200},
+		},
+		{
+			name:   "no subscriptions in window",
+			userID: &otherUser,
+			from:   month(2023, 1),
+			to:     month(2023, 6),
+			want:   map[string]int{},
+		},
+	}
+
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			repo := newRepo(t)
+			seed(t, repo)
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					got, err := repo.CalculateTotal(context.Background(), model.SubscriptionFilter{UserID: tt.userID, ServiceName: tt.serviceName}, tt.from, tt.to)
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, got)
+				})
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        *uuid.UUID
+		serviceName   *string
+		limit, offset int
+		want          []string
+		wantTotal     int64
+	}{
+		{
+			name:      "all",
+			limit:     -1,
+			want:      []string{"Netflix", "Spotify", "Yandex Plus", "Netflix", "Domain"},
+			wantTotal: 5,
+		},
+		{
+			name:      "user",
+			userID:    &otherUser,
+			limit:     -1,
+			want:      []string{"Netflix"},
+			wantTotal: 1,
+		},
+		{
+			name:        "service",
+			serviceName: ptr("netflix"),
+			limit:       -1,
+			want:        []string{"Netflix", "Netflix"},
+			wantTotal:   2,
+		},
+		{
+			name:      "page",
+			userID:    &testUser,
+			limit:     2,
+			offset:    3,
+			want:      []string{"Domain"},
+			wantTotal: 4,
+		},
+		{
+			name:      "offset past end",
+			userID:    &testUser,
+			limit:     10,
+			offset:    10,
+			want:      []string{},
+			wantTotal: 4,
+		},
+	}
+
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			repo := newRepo(t)
+			seed(t, repo)
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					list, err := repo.List(context.Background(), model.SubscriptionFilter{UserID: tt.userID, ServiceName: tt.serviceName}, tt.limit, tt.offset)
+					require.NoError(t, err)
+					names := []string{}
+					for _, sub := range list.Items {
+						names = append(names, sub.ServiceName)
+					}
+					// Порядок строк в SQL без ORDER BY не определён
+					if tt.limit < 0 {
+						assert.ElementsMatch(t, tt.want, names)
+					} else {
+						assert.Len(t, names, len(tt.want))
+					}
+					assert.Equal(t, tt.wantTotal, list.Total)
+				})
+			}
+		})
+	}
+}
+
+func TestCRUD(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			sub := &model.Subscription{ServiceName: "Netflix", Price: 1000, UserID: testUser, StartDate: month(2024, 1)}
+			require.NoError(t, repo.Create(ctx, sub))
+			require.NotEqual(t, uuid.Nil, sub.ID)
+
+			got, err := repo.GetByID(ctx, sub.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Netflix", got.ServiceName)
+			assert.True(t, sub.StartDate.Equal(got.StartDate))
+
+			sub.Price = 1200
+			sub.EndDate = ptr(month(2024, 12))
+			require.NoError(t, repo.Update(ctx, sub))
+			got, err = repo.GetByID(ctx, sub.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 1200, got.Price)
+			require.NotNil(t, got.EndDate)
+
+			// Изменение полученной копии не затрагивает хранилище
+			got.EndDate = ptr(month(2030, 1))
+			again, err := repo.GetByID(ctx, sub.ID)
+			require.NoError(t, err)
+			assert.True(t, again.EndDate.Equal(month(2024, 12)))
+
+			assert.ErrorIs(t, repo.Create(ctx, &model.Subscription{ID: sub.ID, ServiceName: "Netflix", UserID: testUser, StartDate: month(2024, 1)}), ErrConflict)
+
+			require.NoError(t, repo.Delete(ctx, sub.ID))
+			_, err = repo.GetByID(ctx, sub.ID)
+			assert.ErrorIs(t, err, ErrNotFound)
+			assert.ErrorIs(t, repo.Delete(ctx, sub.ID), ErrNotFound)
+			assert.ErrorIs(t, repo.Update(ctx, sub), ErrNotFound)
+		})
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			repo := newRepo(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := repo.Create(ctx, &model.Subscription{ServiceName: "Netflix", Price: 1000, UserID: testUser, StartDate: month(2024, 1)})
+			assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+			_, err = repo.List(ctx, model.SubscriptionFilter{}, -1, 0)
+			assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+			_, err = repo.CalculateTotal(ctx, model.SubscriptionFilter{}, month(2024, 1), month(2024, 12))
+			assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+		})
+	}
+}
+
+func TestPriceChanges(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			sub := &model.Subscription{
+				ServiceName: "Netflix", Price: 1000, Currency: "RUB", UserID: testUser, StartDate: month(2024, 1),
+				EndDate: ptr(month(2024, 12)), BillingPeriod: model.BillingMonth, BillingInterval: 1,
+			}
+			require.NoError(t, repo.Create(ctx, sub))
+
+			// Изменения добавляются не по порядку, список и суммы от этого не зависят
+			later := &model.PriceChange{SubscriptionID: sub.ID, EffectiveFrom: month(2024, 10), Price: 1500}
+			require.NoError(t, repo.CreatePriceChange(ctx, later))
+			require.NoError(t, repo.CreatePriceChange(ctx, &model.PriceChange{SubscriptionID: sub.ID, EffectiveFrom: month(2024, 4), Price: 1200}))
+
+			assert.ErrorIs(t, repo.CreatePriceChange(ctx, &model.PriceChange{SubscriptionID: sub.ID, EffectiveFrom: month(2024, 4), Price: 1300}), ErrConflict)
+			assert.ErrorIs(t, repo.CreatePriceChange(ctx, &model.PriceChange{SubscriptionID: uuid.New(), EffectiveFrom: month(2024, 4), Price: 1300}), ErrNotFound)
+
+			changes, err := repo.ListPriceChanges(ctx, sub.ID)
+			require.NoError(t, err)
+			require.Len(t, changes, 2)
+			assert.Equal(t, 1200, changes[0].Price)
+			assert.Equal(t, 1500, changes[1].Price)
+
+			// Январь–март по 1000, апрель–сентябрь по 1200, октябрь–декабрь по 1500
+			total, err := repo.CalculateTotal(ctx, model.SubscriptionFilter{UserID: &testUser}, month(2024, 1), month(2024, 12))
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"RUB": 1000*3 + 1200*6 + 1500*3}, total)
+
+			require.NoError(t, repo.DeletePriceChange(ctx, sub.ID, later.ID))
+			assert.ErrorIs(t, repo.DeletePriceChange(ctx, sub.ID, later.ID), ErrNotFound)
+			total, err = repo.CalculateTotal(ctx, model.SubscriptionFilter{UserID: &testUser}, month(2024, 1), month(2024, 12))
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"RUB": 1000*3 + 1200*9}, total)
+		})
+	}
+}
+
+func TestPauses(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			sub := &model.Subscription{
+				ServiceName: "Netflix", Price: 1000, Currency: "RUB", UserID: testUser, StartDate: month(2024, 1),
+				EndDate: ptr(month(2024, 12)), BillingPeriod: model.BillingMonth, BillingInterval: 1,
+			}
+			require.NoError(t, repo.Create(ctx, sub))
+
+			closed := &model.Pause{SubscriptionID: sub.ID, PausedFrom: month(2024, 3)}
+			require.NoError(t, repo.CreatePause(ctx, closed))
+			assert.ErrorIs(t, repo.CreatePause(ctx, &model.Pause{SubscriptionID: sub.ID, PausedFrom: month(2024, 4)}), ErrConflict)
+			closed.ResumedAt = ptr(month(2024, 5))
+			require.NoError(t, repo.UpdatePause(ctx, closed))
+			require.NoError(t, repo.CreatePause(ctx, &model.Pause{SubscriptionID: sub.ID, PausedFrom: month(2024, 10)}))
+
+			pauses, err := repo.ListPauses(ctx, sub.ID)
+			require.NoError(t, err)
+			require.Len(t, pauses, 2)
+			require.NotNil(t, pauses[0].ResumedAt)
+			assert.True(t, pauses[0].ResumedAt.Equal(month(2024, 5)))
+			assert.Nil(t, pauses[1].ResumedAt)
+
+			// Март, апрель и октябрь–декабрь приостановлены
+			total, err := repo.CalculateTotal(ctx, model.SubscriptionFilter{UserID: &testUser}, month(2024, 1), month(2024, 12))
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"RUB": 1000 * 7}, total)
+
+			for _, tt := range []struct {
+				month time.Time
+				want  int64
+			}{
+				{month: month(2024, 2), want: 1},
+				{month: month(2024, 4), want: 0},
+				{month: month(2024, 5), want: 1},
+				{month: month(2024, 11), want: 0},
+			} {
+				list, err := repo.List(ctx, model.SubscriptionFilter{ActiveIn: &tt.month}, -1, 0)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, list.Total, "active in %s", tt.month.Format("01-2006"))
+			}
+		})
+	}
+}
+
+func TestTrials(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			sub := &model.Subscription{
+				ServiceName: "Netflix", Price: 1000, Currency: "RUB", UserID: testUser, StartDate: month(2024, 1),
+				EndDate: ptr(month(2024, 12)), TrialEndDate: ptr(month(2024, 3)),
+				BillingPeriod: model.BillingMonth, BillingInterval: 1,
+			}
+			require.NoError(t, repo.Create(ctx, sub))
+
+			// Январь и февраль бесплатны
+			total, err := repo.CalculateTotal(ctx, model.SubscriptionFilter{UserID: &testUser}, month(2024, 1), month(2024, 12))
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"RUB": 1000 * 10}, total)
+
+			subs, err := repo.ListTrialsEnding(ctx, model.SubscriptionFilter{}, month(2024, 3), month(2024, 4))
+			require.NoError(t, err)
+			require.Len(t, subs, 1)
+			assert.Equal(t, sub.ID, subs[0].ID)
+
+			subs, err = repo.ListTrialsEnding(ctx, model.SubscriptionFilter{}, month(2024, 4), month(2024, 5))
+			require.NoError(t, err)
+			assert.Empty(t, subs)
+		})
+	}
+}
+
+func TestListCharges(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			seed(t, repo)
+
+			// Пробный январь, пауза в марте и новая цена с апреля; бессрочные подписки списываются и в будущем
+			kinopoisk := &model.Subscription{
+				ServiceName: "Kinopoisk", Price: 400, Currency: "RUB", UserID: otherUser, StartDate: month(2030, 1),
+				TrialEndDate: ptr(month(2030, 2)), BillingPeriod: model.BillingMonth, BillingInterval: 1,
+			}
+			require.NoError(t, repo.Create(ctx, kinopoisk))
+			require.NoError(t, repo.CreatePriceChange(ctx, &model.PriceChange{SubscriptionID: kinopoisk.ID, EffectiveFrom: month(2030, 4), Price: 500}))
+			require.NoError(t, repo.CreatePause(ctx, &model.Pause{SubscriptionID: kinopoisk.ID, PausedFrom: month(2030, 3), ResumedAt: ptr(month(2030, 4))}))
+
+			charges, err := repo.ListCharges(ctx, model.SubscriptionFilter{UserID: &otherUser}, month(2030, 1), month(2030, 6))
+			require.NoError(t, err)
+			type charge struct {
+				service string
+				date    time.Time
+				amount  int
+			}
+			got := []charge{}
+			for _, ch := range charges {
+				assert.Equal(t, "RUB", ch.Currency)
+				got = append(got, charge{ch.ServiceName, ch.Date.UTC(), ch.Amount})
+			}
+			assert.Equal(t, []charge{
+				{"Netflix", month(2030, 1), 1000},
+				{"Kinopoisk", month(2030, 2), 400},
+				{"Netflix", month(2030, 2), 1000},
+				{"Netflix", month(2030, 3), 1000},
+				{"Kinopoisk", month(2030, 4), 500},
+				{"Netflix", month(2030, 4), 1000},
+				{"Kinopoisk", month(2030, 5), 500},
+				{"Netflix", month(2030, 5), 1000},
+			}, got)
+		})
+	}
+}
+
+func TestCalculateBreakdown(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			repo := newRepo(t)
+			seed(t, repo)
+
+			items, err := repo.CalculateBreakdown(context.Background(), model.SubscriptionFilter{UserID: &testUser}, month(2024, 5), month(2024, 6))
+			require.NoError(t, err)
+			for i := range items {
+				items[i].Month = items[i].Month.UTC()
+			}
+			assert.Equal(t, []model.BreakdownItem{
+				{Month: month(2024, 5), ServiceName: "Netflix", Amount: 1000, Currency: "RUB"},
+				{Month: month(2024, 5), ServiceName: "Spotify", Amount: 500, Currency: "RUB"},
+				{Month: month(2024, 6), ServiceName: "Domain", Amount: 1200, Currency: "USD"},
+				{Month: month(2024, 6), ServiceName: "Netflix", Amount: 1000, Currency: "RUB"},
+			}, items)
+		})
+	}
+}
+
+func TestForecastByMonth(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			seed(t, repo)
+
+			// Прогноз учитывает только будущие списания, в том числе бессрочных подписок
+			amounts, err := repo.ForecastByMonth(ctx, model.SubscriptionFilter{UserID: &testUser}, month(2030, 5), month(2030, 7))
+			require.NoError(t, err)
+			for i := range amounts {
+				amounts[i].Month = amounts[i].Month.UTC()
+			}
+			assert.Equal(t, []model.MonthlyAmount{
+				{Month: month(2030, 5), Amount: 1000 + 300, Currency: "RUB"},
+				{Month: month(2030, 6), Amount: 1000 + 300, Currency: "RUB"},
+				{Month: month(2030, 6), Amount: 1200, Currency: "USD"},
+			}, amounts)
+		})
+	}
+}
+
+func TestBudgets(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+
+			b := &model.Budget{UserID: testUser, Amount: 150000, Currency: "RUB"}
+			require.NoError(t, repo.CreateBudget(ctx, b))
+			require.NoError(t, repo.CreateBudget(ctx, &model.Budget{UserID: otherUser, Amount: 1000, Currency: "USD"}))
+			assert.ErrorIs(t, repo.CreateBudget(ctx, &model.Budget{UserID: testUser, Amount: 1000, Currency: "RUB"}), ErrConflict)
+
+			b.Amount = 200000
+			require.NoError(t, repo.UpdateBudget(ctx, b))
+			got, err := repo.GetBudget(ctx, b.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 200000, got.Amount)
+
+			budgets, err := repo.ListBudgets(ctx, &testUser)
+			require.NoError(t, err)
+			require.Len(t, budgets, 1)
+			assert.Equal(t, b.ID, budgets[0].ID)
+			budgets, err = repo.ListBudgets(ctx, nil)
+			require.NoError(t, err)
+			assert.Len(t, budgets, 2)
+
+			require.NoError(t, repo.DeleteBudget(ctx, b.ID))
+			_, err = repo.GetBudget(ctx, b.ID)
+			assert.ErrorIs(t, err, ErrNotFound)
+			assert.ErrorIs(t, repo.DeleteBudget(ctx, b.ID), ErrNotFound)
+			assert.ErrorIs(t, repo.UpdateBudget(ctx, b), ErrNotFound)
+		})
+	}
+}
+
+func TestCategoriesAndTags(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			subs := []*model.Subscription{
+				{ServiceName: "Netflix", Category: "entertainment", Tags: []string{"family", "video"}},
+				{ServiceName: "Spotify", Category: "entertainment", Tags: []string{"music"}},
+				{ServiceName: "Jira", Category: "work tools", Tags: []string{"family"}},
+				{ServiceName: "Domain"},
+			}
+			for _, sub := range subs {
+				sub.Price, sub.Currency, sub.UserID, sub.StartDate = 1000, "RUB", testUser, month(2024, 1)
+				sub.EndDate = ptr(month(2024, 3))
+				sub.BillingPeriod, sub.BillingInterval = model.BillingMonth, 1
+				require.NoError(t, repo.Create(ctx, sub))
+			}
+
+			got, err := repo.GetByID(ctx, subs[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"family", "video"}, got.Tags)
+
+			tests := []struct {
+				name   string
+				filter model.SubscriptionFilter
+				want   []string
+			}{
+				{name: "category", filter: model.SubscriptionFilter{Category: ptr("entertainment")}, want: []string{"Netflix", "Spotify"}},
+				{name: "without category", filter: model.SubscriptionFilter{Category: ptr("")}, want: []string{"Domain"}},
+				{name: "tag", filter: model.SubscriptionFilter{Tag: ptr("family")}, want: []string{"Netflix", "Jira"}},
+				{name: "category and tag", filter: model.SubscriptionFilter{Category: ptr("entertainment"), Tag: ptr("family")}, want: []string{"Netflix"}},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					list, err := repo.List(ctx, tt.filter, -1, 0)
+					require.NoError(t, err)
+					names := []string{}
+					for _, sub := range list.Items {
+						names = append(names, sub.ServiceName)
+					}
+					assert.ElementsMatch(t, tt.want, names)
+
+					total, err := repo.CalculateTotal(ctx, tt.filter, month(2024, 1), month(2024, 12))
+					require.NoError(t, err)
+					assert.Equal(t, map[string]int{"RUB": 3000 * len(tt.want)}, total)
+				})
+			}
+
+			amounts, err := repo.CalculateTotalByCategory(ctx, model.SubscriptionFilter{UserID: &testUser}, month(2024, 1), month(2024, 12))
+			require.NoError(t, err)
+			assert.Equal(t, []model.CategoryAmount{
+				{Category: "", Amount: 3000, Currency: "RUB"},
+				{Category: "entertainment", Amount: 6000, Currency: "RUB"},
+				{Category: "work tools", Amount: 3000, Currency: "RUB"},
+			}, amounts)
+
+			// Обновление заменяет метки целиком
+			subs[0].Tags = []string{"video"}
+			require.NoError(t, repo.Update(ctx, subs[0]))
+			list, err := repo.List(ctx, model.SubscriptionFilter{Tag: ptr("family")}, -1, 0)
+			require.NoError(t, err)
+			require.Len(t, list.Items, 1)
+			assert.Equal(t, "Jira", list.Items[0].ServiceName)
+		})
+	}
+}
+
+func TestServices(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			netflix := &model.Service{Name: "Netflix", Aliases: []string{"netflix", "нетфликс"}}
+			require.NoError(t, repo.CreateService(ctx, netflix))
+			assert.ErrorIs(t, repo.CreateService(ctx, &model.Service{Name: "Other", Aliases: []string{"нетфликс"}}), ErrConflict)
+
+			found, err := repo.FindServiceByAlias(ctx, "нетфликс")
+			require.NoError(t, err)
+			assert.Equal(t, netflix.ID, found.ID)
+			_, err = repo.FindServiceByAlias(ctx, "spotify")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			subs := []*model.Subscription{
+				{ServiceName: "Netflix", ServiceID: &netflix.ID},
+				{ServiceName: " NETFLIX\t"},
+				{ServiceName: "Нетфликс"},
+				{ServiceName: "Spotify"},
+			}
+			for _, sub := range subs {
+				sub.Price, sub.Currency, sub.UserID, sub.StartDate = 1000, "RUB", testUser, month(2024, 1)
+				sub.BillingPeriod, sub.BillingInterval = model.BillingMonth, 1
+				require.NoError(t, repo.Create(ctx, sub))
+			}
+
+			tests := []struct {
+				name   string
+				filter model.SubscriptionFilter
+				want   []*model.Subscription
+			}{
+				{name: "normalized name", filter: model.SubscriptionFilter{ServiceName: ptr("netflix")}, want: subs[:2]},
+				{name: "service with unlinked aliases", filter: model.SubscriptionFilter{ServiceID: &netflix.ID}, want: subs[:3]},
+				{name: "unknown service", filter: model.SubscriptionFilter{ServiceID: ptr(uuid.New())}, want: nil},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					list, err := repo.List(ctx, tt.filter, -1, 0)
+					require.NoError(t, err)
+					var got, want []uuid.UUID
+					for _, sub := range list.Items {
+						got = append(got, sub.ID)
+					}
+					for _, sub := range tt.want {
+						want = append(want, sub.ID)
+					}
+					assert.ElementsMatch(t, want, got)
+				})
+			}
+		})
+	}
+}
+
+func TestCancellations(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			seed(t, repo)
+			list, err := repo.List(ctx, model.SubscriptionFilter{UserID: &testUser}, -1, 0)
+			require.NoError(t, err)
+
+			canceled := []struct {
+				at     time.Time
+				reason model.CancelReason
+			}{
+				{at: time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC), reason: model.CancelTooExpensive},
+				{at: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), reason: model.CancelNotUsing},
+				{at: time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC), reason: model.CancelTooExpensive},
+			}
+			for i, c := range canceled {
+				sub := list.Items[i]
+				sub.EndDate, sub.CanceledAt, sub.CancelReason = ptr(month(2024, 9)), ptr(c.at), c.reason
+				require.NoError(t, repo.Cancel(ctx, &sub))
+			}
+
+			got, err := repo.GetByID(ctx, list.Items[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.CancelTooExpensive, got.CancelReason)
+			assert.Equal(t, month(2024, 9), *got.EndDate)
+
+			tests := []struct {
+				name        string
+				from, until *time.Time
+				want        []model.ReasonCount
+			}{
+				{name: "all", want: []model.ReasonCount{{Reason: model.CancelTooExpensive, Count: 2}, {Reason: model.CancelNotUsing, Count: 1}}},
+				{name: "july", from: ptr(month(2024, 7)), until: ptr(month(2024, 8)), want: []model.ReasonCount{{Reason: model.CancelNotUsing, Count: 1}, {Reason: model.CancelTooExpensive, Count: 1}}},
+				{name: "none", from: ptr(month(2024, 8)), want: []model.ReasonCount{}},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					counts, err := repo.CountCancelReasons(ctx, model.SubscriptionFilter{}, tt.from, tt.until)
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, counts)
+				})
+			}
+		})
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			seed(t, repo)
+			list, err := repo.List(ctx, model.SubscriptionFilter{UserID: &otherUser}, -1, 0)
+			require.NoError(t, err)
+			require.Len(t, list.Items, 1)
+			id := list.Items[0].ID
+
+			assert.ErrorIs(t, repo.Restore(ctx, id), ErrNotFound, "not deleted")
+			require.NoError(t, repo.Delete(ctx, id))
+			assert.ErrorIs(t, repo.Delete(ctx, id), ErrNotFound)
+			_, err = repo.GetByID(ctx, id)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			tests := []struct {
+				name      string
+				filter    model.SubscriptionFilter
+				wantTotal int64
+			}{
+				{name: "hidden", filter: model.SubscriptionFilter{UserID: &otherUser}, wantTotal: 0},
+				{name: "include deleted", filter: model.SubscriptionFilter{UserID: &otherUser, IncludeDeleted: true}, wantTotal: 1},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					list, err := repo.List(ctx, tt.filter, -1, 0)
+					require.NoError(t, err)
+					assert.Equal(t, tt.wantTotal, list.Total)
+				})
+			}
+
+			require.NoError(t, repo.Restore(ctx, id))
+			_, err = repo.GetByID(ctx, id)
+			require.NoError(t, err)
+
+			require.NoError(t, repo.Delete(ctx, id))
+			purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Empty(t, purged, "deleted less than retention ago")
+			purged, err = repo.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			require.Len(t, purged, 1)
+			assert.Equal(t, id, purged[0].ID)
+			assert.ErrorIs(t, repo.Restore(ctx, id), ErrNotFound)
+		})
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			subID := uuid.New()
+			at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			records := []*model.AuditRecord{
+				{SubscriptionID: subID, UserID: testUser, Actor: "alice", Operation: model.AuditCreate, CreatedAt: at},
+				{SubscriptionID: subID, UserID: testUser, Actor: "bob", Operation: model.AuditUpdate, CreatedAt: at.Add(time.Hour),
+					Changes: map[string]model.FieldChange{"price": {Before: float64(100), After: float64(200)}}},
+				{SubscriptionID: uuid.New(), UserID: otherUser, Actor: "alice", Operation: model.AuditCreate, CreatedAt: at.Add(2 * time.Hour)},
+			}
+			for _, rec := range records {
+				if rec.Changes == nil {
+					rec.Changes = map[string]model.FieldChange{}
+				}
+				require.NoError(t, repo.CreateAuditRecord(ctx, rec))
+			}
+
+			tests := []struct {
+				name   string
+				filter model.AuditFilter
+				want   []*model.AuditRecord
+			}{
+				{name: "all", want: records},
+				{name: "subscription", filter: model.AuditFilter{SubscriptionID: &subID}, want: records[:2]},
+				{name: "actor", filter: model.AuditFilter{Actor: ptr("alice")}, want: []*model.AuditRecord{records[0], records[2]}},
+				{name: "operation", filter: model.AuditFilter{Operation: ptr(model.AuditUpdate)}, want: records[1:2]},
+				{name: "period", filter: model.AuditFilter{Since: ptr(at.Add(time.Hour)), Until: ptr(at.Add(2 * time.Hour))}, want: records[1:2]},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					list, err := repo.ListAuditRecords(ctx, tt.filter, -1, 0)
+					require.NoError(t, err)
+					require.Len(t, list.Items, len(tt.want))
+					assert.Equal(t, int64(len(tt.want)), list.Total)
+					for i, rec := range tt.want {
+						assert.Equal(t, rec.ID, list.Items[i].ID)
+					}
+				})
+			}
+
+			list, err := repo.ListAuditRecords(ctx, model.AuditFilter{Operation: ptr(model.AuditUpdate)}, -1, 0)
+			require.NoError(t, err)
+			assert.Equal(t, records[1].Changes, list.Items[0].Changes)
+		})
+	}
+}
+
+func TestVersions(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			sub := &model.Subscription{
+				ServiceName: "Netflix", Price: 1000, Currency: "RUB", UserID: testUser, StartDate: month(2024, 1),
+				BillingPeriod: model.BillingMonth, BillingInterval: 1,
+			}
+			require.NoError(t, repo.Create(ctx, sub))
+			assert.Equal(t, int64(1), sub.Version)
+
+			stale := *sub
+			sub.Price = 1200
+			require.NoError(t, repo.Update(ctx, sub))
+			assert.Equal(t, int64(2), sub.Version)
+
+			stale.Price = 1500
+			assert.ErrorIs(t, repo.Update(ctx, &stale), ErrVersionMismatch)
+			stale.CanceledAt, stale.CancelReason = ptr(time.Now().UTC()), model.CancelOther
+			assert.ErrorIs(t, repo.Cancel(ctx, &stale), ErrVersionMismatch)
+
+			sub.CanceledAt, sub.CancelReason = ptr(time.Now().UTC()), model.CancelOther
+			require.NoError(t, repo.Cancel(ctx, sub))
+			got, err := repo.GetByID(ctx, sub.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 1200, got.Price)
+			assert.Equal(t, int64(3), got.Version)
+		})
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			var subs []*model.Subscription
+			for _, name := range []string{"Netflix", "Spotify", "Domain"} {
+				subs = append(subs, &model.Subscription{
+					ServiceName: name, Price: 1000, Currency: "RUB", UserID: testUser, StartDate: month(2024, 1),
+					BillingPeriod: model.BillingMonth, BillingInterval: 1, Tags: []string{"imported"},
+				})
+			}
+			require.NoError(t, repo.CreateBatch(ctx, subs))
+
+			list, err := repo.List(ctx, model.SubscriptionFilter{Tag: ptr("imported")}, -1, 0)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(subs)), list.Total)
+			for _, sub := range subs {
+				assert.NotEqual(t, uuid.Nil, sub.ID)
+				assert.Equal(t, int64(1), sub.Version)
+			}
+
+			// Повтор существующего id отменяет весь пакет
+			dup := *subs[0]
+			fresh := &model.Subscription{
+				ServiceName: "Jira", Price: 1000, Currency: "RUB", UserID: testUser, StartDate: month(2024, 1),
+				BillingPeriod: model.BillingMonth, BillingInterval: 1,
+			}
+			assert.ErrorIs(t, repo.CreateBatch(ctx, []*model.Subscription{fresh, &dup}), ErrConflict)
+			_, err = repo.GetByID(ctx, fresh.ID)
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestLockUser(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+
+			// Вторая транзакция ждёт, пока первая не зафиксирует подписку, и видит её
+			locked := make(chan struct{})
+			done := make(chan error, 1)
+			go func() {
+				done <- repo.Transaction(ctx, func(tx Repository) error {
+					if err := tx.LockUser(ctx, testUser); err != nil {
+						return err
+					}
+					close(locked)
+					time.Sleep(50 * time.Millisecond)
+					return tx.Create(ctx, &model.Subscription{
+						ServiceName: "Netflix", Price: 1000, Currency: "RUB", UserID: testUser, StartDate: month(2024, 1),
+						BillingPeriod: model.BillingMonth, BillingInterval: 1,
+					})
+				})
+			}()
+
+			<-locked
+			var total int64
+			err := repo.Transaction(ctx, func(tx Repository) error {
+				if err := tx.LockUser(ctx, testUser); err != nil {
+					return err
+				}
+				list, err := tx.List(ctx, model.SubscriptionFilter{UserID: ptr(testUser)}, -1, 0)
+				if err != nil {
+					return err
+				}
+				total = list.Total
+				return nil
+			})
+			require.NoError(t, err)
+			require.NoError(t, <-done)
+			assert.Equal(t, int64(1), total)
+		})
+	}
+}
+
+func TestListInBatches(t *testing.T) {
+	for repoName, newRepo := range testRepos(t) {
+		t.Run(repoName, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			seed(t, repo)
+
+			tests := []struct {
+				name      string
+				filter    model.SubscriptionFilter
+				batchSize int
+				want      []int
+			}{
+				{name: "all", batchSize: 2, want: []int{2, 2, 1}},
+				{name: "exact", filter: model.SubscriptionFilter{UserID: &testUser}, batchSize: 2, want: []int{2, 2}},
+				{name: "empty", filter: model.SubscriptionFilter{ServiceName: ptr("jira")}, batchSize: 2, want: nil},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					var sizes []int
+					seen := map[uuid.UUID]bool{}
+					err := repo.ListInBatches(ctx, tt.filter, tt.batchSize, func(batch []model.Subscription) error {
+						sizes = append(sizes, len(batch))
+						for _, sub := range batch {
+							assert.False(t, seen[sub.ID], "subscription listed twice")
+							seen[sub.ID] = true
+						}
+						return nil
+					})
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, sizes)
+				})
+			}
+
+			// Ошибка fn прекращает выгрузку
+			errStop := errors.New("stop")
+			calls := 0
+			err := repo.ListInBatches(ctx, model.SubscriptionFilter{}, 2, func([]model.Subscription) error {
+				calls++
+				return errStop
+			})
+			assert.ErrorIs(t, err, errStop)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
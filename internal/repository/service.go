@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *repo) CreateService(ctx context.Context, svc *model.Service) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if svc.ID == uuid.Nil {
+		svc.ID = uuid.New()
+	}
+	return mapErr(db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(svc).Error; err != nil {
+			return err
+		}
+		return setAliases(tx, svc.ID, svc.Aliases)
+	}))
+}
+
+func (r *repo) GetService(ctx context.Context, id uuid.UUID) (*model.Service, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var svc model.Service
+	if err := db.First(&svc, "id = ?", id).Error; err != nil {
+		return nil, mapErr(err)
+	}
+	services := []model.Service{svc}
+	if err := loadAliases(db, services); err != nil {
+		return nil, err
+	}
+	return &services[0], nil
+}
+
+func (r *repo) UpdateService(ctx context.Context, svc *model.Service) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return mapErr(db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(svc).Select("*").Omit("id", "created_at").Updates(svc)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return setAliases(tx, svc.ID, svc.Aliases)
+	}))
+}
+
+func (r *repo) DeleteService(ctx context.Context, id uuid.UUID) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// Псевдонимы удаляются каскадно, у подписок service_id обнуляется
+	res := db.Delete(&model.Service{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) ListServices(ctx context.Context) ([]model.Service, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	services := []model.Service{}
+	if err := db.Order("name").Find(&services).Error; err != nil {
+		return nil, err
+	}
+	if err := loadAliases(db, services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+func (r *repo) FindServiceByAlias(ctx context.Context, alias string) (*model.Service, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var svc model.Service
+	if err := db.Joins("JOIN service_aliases sa ON sa.service_id = services.id").
+		Where("sa.alias = ?", alias).
+		First(&svc).Error; err != nil {
+		return nil, mapErr(err)
+	}
+	services := []model.Service{svc}
+	if err := loadAliases(db, services); err != nil {
+		return nil, err
+	}
+	return &services[0], nil
+}
+
+// setAliases заменяет псевдонимы сервиса. Вызывается внутри транзакции сохранения сервиса;
+// первичный ключ service_aliases не даёт двум сервисам занять один псевдоним.
+func setAliases(tx *gorm.DB, serviceID uuid.UUID, aliases []string) error {
+	if err := tx.Where("service_id = ?", serviceID).Delete(&model.ServiceAlias{}).Error; err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+	rows := make([]model.ServiceAlias, 0, len(aliases))
+	for _, alias := range aliases {
+		rows = append(rows, model.ServiceAlias{Alias: alias, ServiceID: serviceID})
+	}
+	return tx.Omit("Service").Create(&rows).Error
+}
+
+// loadAliases заполняет Aliases у сервисов одним запросом.
+func loadAliases(db *gorm.DB, services []model.Service) error {
+	if len(services) == 0 {
+		return nil
+	}
+	index := make(map[uuid.UUID]int, len(services))
+	ids := make([]uuid.UUID, 0, len(services))
+	for i, svc := range services {
+		index[svc.ID] = i
+		ids = append(ids, svc.ID)
+	}
+
+	var rows []model.ServiceAlias
+	if err := db.Where("service_id IN ?", ids).Order("alias").Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		i := index[row.ServiceID]
+		services[i].Aliases = append(services[i].Aliases, row.Alias)
+	}
+	return nil
+}
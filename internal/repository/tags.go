@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// setTags заменяет метки подписки на tags, создавая недостающие записи в tags.
+// Вызывается внутри транзакции сохранения подписки.
+func setTags(tx *gorm.DB, subscriptionID uuid.UUID, tags []string) error {
+	if err := tx.Where("subscription_id = ?", subscriptionID).Delete(&model.SubscriptionTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	newTags := make([]model.Tag, 0, len(tags))
+	for _, name := range tags {
+		newTags = append(newTags, model.Tag{ID: uuid.New(), Name: name})
+	}
+	if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&newTags).Error; err != nil {
+		return err
+	}
+
+	var existing []model.Tag
+	if err := tx.Where("name IN ?", tags).Find(&existing).Error; err != nil {
+		return err
+	}
+	links := make([]model.SubscriptionTag, 0, len(existing))
+	for _, tag := range existing {
+		links = append(links, model.SubscriptionTag{SubscriptionID: subscriptionID, TagID: tag.ID})
+	}
+	return tx.Omit("Subscription", "Tag").Create(&links).Error
+}
+
+// loadTags заполняет Tags у подписок одним запросом.
+func loadTags(db *gorm.DB, subs []model.Subscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+	index := make(map[uuid.UUID]int, len(subs))
+	ids := make([]uuid.UUID, 0, len(subs))
+	for i, sub := range subs {
+		index[sub.ID] = i
+		ids = append(ids, sub.ID)
+	}
+
+	var rows []struct {
+		SubscriptionID uuid.UUID
+		Name           string
+	}
+	if err := db.Table("subscription_tags AS st").
+		Select("st.subscription_id, t.name").
+		Joins("JOIN tags t ON t.id = st.tag_id").
+		Where("st.subscription_id IN ?", ids).
+		Order("t.name").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		i := index[row.SubscriptionID]
+		subs[i].Tags = append(subs[i].Tags, row.Name)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package statement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CSVOptions настройки разбора CSV-выписки. Колонки без явного имени ищутся
+// в заголовке по распространённым названиям.
+type CSVOptions struct {
+	Delimiter      rune
+	DateColumn     string
+	AmountColumn   string
+	PayeeColumn    string
+	CurrencyColumn string
+	// DateLayout формат даты в нотации Go; пусто — форматы из dateLayouts по очереди
+	DateLayout string
+	// Currency валюта операций, если в выписке нет колонки валюты
+	Currency string
+}
+
+// dateLayouts форматы дат, которые распознаются без явного DateLayout.
+// Форматы вида 01/02/2006 неоднозначны, их нужно задавать явно.
+var dateLayouts = []string{
+	"2006-01-02", "02.01.2006", "2006-01-02 15:04:05", "02.01.2006 15:04:05",
+	"02.01.2006 15:04", "2006/01/02", time.RFC3339,
+}
+
+// csvColumnNames названия колонок, которые ищутся в заголовке без учёта регистра.
+var csvColumnNames = map[string][]string{
+	"date": {"date", "transaction date", "posting date", "booking date", "posted",
+		"дата", "дата операции", "дата платежа"},
+	"amount":   {"amount", "sum", "сумма", "сумма операции", "сумма платежа"},
+	"payee":    {"payee", "merchant", "description", "name", "details", "получатель", "описание"},
+	"currency": {"currency", "валюта", "валюта операции"},
+}
+
+// ParseCSV читает выписку в CSV с заголовком: дата, сумма (списания отрицательные),
+// получатель и необязательная валюта операции.
+func ParseCSV(r io.Reader, opts CSVOptions) ([]Transaction, error) {
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		byName[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	column := func(kind, name string, required bool) (int, error) {
+		if name != "" {
+			if i, ok := byName[strings.ToLower(strings.TrimSpace(name))]; ok {
+				return i, nil
+			}
+			return -1, fmt.Errorf("column %q not found", name)
+		}
+		for _, candidate := range csvColumnNames[kind] {
+			if i, ok := byName[candidate]; ok {
+				return i, nil
+			}
+		}
+		if required {
+			return -1, fmt.Errorf("%s column not found, set it explicitly", kind)
+		}
+		return -1, nil
+	}
+	dateCol, err := column("date", opts.DateColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	amountCol, err := column("amount", opts.AmountColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	payeeCol, err := column("payee", opts.PayeeColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	currencyCol, err := column("currency", opts.CurrencyColumn, false)
+	if err != nil {
+		return nil, err
+	}
+
+	layouts := dateLayouts
+	if opts.DateLayout != "" {
+		layouts = []string{opts.DateLayout}
+	}
+
+	var txs []Transaction
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		cell := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		tx := Transaction{Payee: cell(payeeCol), Currency: strings.ToUpper(firstNonEmpty(cell(currencyCol), opts.Currency))}
+		if tx.Date, err = parseDate(cell(dateCol), layouts); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if tx.Amount, err = parseAmount(cell(amountCol), tx.Currency); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// parseDate разбирает дату первым подходящим форматом и отбрасывает время.
+func parseDate(v string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", v)
+}
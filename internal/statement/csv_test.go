@@ -0,0 +1,84 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		opts    CSVOptions
+		want    []Transaction
+		wantErr string
+	}{
+		{
+			name: "russian bank export",
+			data: "\ufeffДата операции;Сумма;Описание;Валюта\n" +
+				"05.01.2025 12:30;-799,00;NETFLIX.COM;rub\n" +
+				"06.01.2025;1 000,00;Зарплата;RUB\n",
+			opts: CSVOptions{Delimiter: ';'},
+			want: []Transaction{
+				{Date: day("2025-01-05"), Amount: -79900, Currency: "RUB", Payee: "NETFLIX.COM"},
+				{Date: day("2025-01-06"), Amount: 100000, Currency: "RUB", Payee: "Зарплата"},
+			},
+		},
+		{
+			name: "explicit columns and currency",
+			data: "when,value,who\n01/15/2025,-9.99,Spotify\n",
+			opts: CSVOptions{DateColumn: "when", AmountColumn: "Value", PayeeColumn: "who", DateLayout: "01/02/2006", Currency: "usd"},
+			want: []Transaction{
+				{Date: day("2025-01-15"), Amount: -999, Currency: "USD", Payee: "Spotify"},
+			},
+		},
+		{
+			name:    "missing amount column",
+			data:    "date,payee\n2025-01-01,Netflix\n",
+			wantErr: "amount column not found, set it explicitly",
+		},
+		{
+			name:    "unknown explicit column",
+			data:    "date,amount,payee\n",
+			opts:    CSVOptions{PayeeColumn: "merchant"},
+			wantErr: `column "merchant" not found`,
+		},
+		{
+			name:    "invalid date",
+			data:    "date,amount,payee\n2025-01-01,-1,Netflix\n01/02/2025,-1,Netflix\n",
+			wantErr: `line 3: invalid date "01/02/2025"`,
+		},
+		{
+			name:    "invalid amount",
+			data:    "date,amount,payee\n2025-01-01,free,Netflix\n",
+			wantErr: `line 2: invalid amount "free"`,
+		},
+		{
+			name:    "empty file",
+			data:    "",
+			wantErr: "file is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txs, err := ParseCSV(strings.NewReader(tt.data), tt.opts)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, txs)
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	got, err := parseDate("2025-01-05T23:30:00+03:00", dateLayouts)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), got)
+}
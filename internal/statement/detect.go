@@ -0,0 +1,178 @@
+package statement
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"subscriptions/internal/model"
+)
+
+// amountTolerance допустимое отклонение суммы от наименьшей в серии: переживает
+// повышение цены, но не смешивает разные тарифы одного получателя
+const amountTolerance = 0.2
+
+// cadence диапазон интервалов между списаниями в днях для периода оплаты.
+// Списания сдвигаются на выходные и праздники, поэтому диапазоны с запасом.
+type cadence struct {
+	period     model.BillingPeriod
+	minDays    int
+	maxDays    int
+	minCharges int
+}
+
+var cadences = []cadence{
+	{model.BillingWeek, 6, 8, 4},
+	{model.BillingMonth, 26, 35, 3},
+	{model.BillingQuarter, 84, 98, 3},
+	{model.BillingYear, 350, 380, 2},
+}
+
+// merchantNoise слова в названии получателя, которые не отличают сервисы друг от друга.
+var merchantNoise = map[string]bool{
+	"www": true, "com": true, "net": true, "org": true, "io": true, "tv": true, "ru": true,
+	"inc": true, "llc": true, "ltd": true, "ооо": true, "pos": true, "payment": true,
+	"purchase": true, "оплата": true, "покупка": true,
+}
+
+// Recurring повторяющееся списание, найденное в выписке.
+type Recurring struct {
+	// Name название получателя без служебных слов и номеров, с заглавных букв
+	Name string
+	// Merchant получатель, как он записан в последней операции серии
+	Merchant string
+	// Amount сумма последнего списания, положительная
+	Amount   int
+	Currency string
+	Period   model.BillingPeriod
+	// Charges даты списаний по порядку
+	Charges []time.Time
+	// Ended после последнего списания выписка продолжается дольше периода оплаты
+	Ended bool
+}
+
+// MerchantKey приводит получателя к виду, по которому операции одного сервиса
+// группируются: нижний регистр, без знаков, номеров и служебных слов.
+// Например, "NETFLIX.COM 866-579-7172" и "Netflix.com" дают "netflix".
+func MerchantKey(payee string) string {
+	words := strings.FieldsFunc(strings.ToLower(payee), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	key := words[:0]
+	for _, w := range words {
+		if merchantNoise[w] || strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			continue
+		}
+		key = append(key, w)
+	}
+	return strings.Join(key, " ")
+}
+
+// Detect ищет среди списаний серии с одним получателем, примерно одинаковой суммой
+// и регулярным интервалом: неделя, месяц, квартал или год. Серии упорядочены
+// по названию получателя.
+func Detect(txs []Transaction) []Recurring {
+	var statementEnd time.Time
+	groups := make(map[string][]Transaction)
+	for _, tx := range txs {
+		if tx.Date.After(statementEnd) {
+			statementEnd = tx.Date
+		}
+		if tx.Amount >= 0 {
+			continue
+		}
+		key := MerchantKey(tx.Payee)
+		if key == "" {
+			continue
+		}
+		tx.Amount = -tx.Amount
+		groups[key+"\x00"+tx.Currency] = append(groups[key+"\x00"+tx.Currency], tx)
+	}
+
+	var found []Recurring
+	for group, charges := range groups {
+		key, _, _ := strings.Cut(group, "\x00")
+		for _, series := range splitByAmount(charges) {
+			c, series, ok := detectCadence(series)
+			if !ok {
+				continue
+			}
+			last := series[len(series)-1]
+			rec := Recurring{
+				Name:     titleCase(key),
+				Merchant: strings.Join(strings.Fields(last.Payee), " "),
+				Amount:   last.Amount,
+				Currency: last.Currency,
+				Period:   c.period,
+				Ended:    last.Date.AddDate(0, 0, c.maxDays).Before(statementEnd),
+			}
+			for _, tx := range series {
+				rec.Charges = append(rec.Charges, tx.Date)
+			}
+			found = append(found, rec)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Name != found[j].Name {
+			return found[i].Name < found[j].Name
+		}
+		return found[i].Charges[0].Before(found[j].Charges[0])
+	})
+	return found
+}
+
+// splitByAmount делит списания получателя на серии с близкими суммами
+// и сортирует каждую серию по дате.
+func splitByAmount(charges []Transaction) [][]Transaction {
+	slices.SortFunc(charges, func(a, b Transaction) int { return a.Amount - b.Amount })
+	var series [][]Transaction
+	start := 0
+	for i := 1; i <= len(charges); i++ {
+		if i < len(charges) && float64(charges[i].Amount) <= float64(charges[start].Amount)*(1+amountTolerance) {
+			continue
+		}
+		s := slices.Clone(charges[start:i])
+		slices.SortStableFunc(s, func(a, b Transaction) int { return a.Date.Compare(b.Date) })
+		series = append(series, s)
+		start = i
+	}
+	return series
+}
+
+// detectCadence подбирает период, с которым в серии идут не меньше minCharges списаний
+// подряд, и возвращает последнюю такую цепочку: разовые и пропущенные списания
+// её прерывают, а последняя цепочка отражает текущую цену.
+func detectCadence(series []Transaction) (cadence, []Transaction, bool) {
+	for _, c := range cadences {
+		var found []Transaction
+		start := 0
+		for i := 1; i <= len(series); i++ {
+			if i < len(series) {
+				days := int(series[i].Date.Sub(series[i-1].Date).Hours() / 24)
+				if days >= c.minDays && days <= c.maxDays {
+					continue
+				}
+			}
+			if i-start >= c.minCharges {
+				found = series[start:i]
+			}
+			start = i
+		}
+		if found != nil {
+			return c, found, true
+		}
+	}
+	return cadence{}, nil, false
+}
+
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
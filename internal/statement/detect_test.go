@@ -0,0 +1,164 @@
+package statement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"subscriptions/internal/model"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func debit(date string, amount int, payee string) Transaction {
+	return Transaction{Date: day(date), Amount: -amount, Currency: "RUB", Payee: payee}
+}
+
+func TestMerchantKey(t *testing.T) {
+	tests := []struct {
+		payee string
+		want  string
+	}{
+		{payee: "NETFLIX.COM 866-579-7172", want: "netflix"},
+		{payee: "Netflix.com", want: "netflix"},
+		{payee: "POS PURCHASE SPOTIFY P1A2B3", want: "spotify"},
+		{payee: "ООО «Яндекс Плюс»", want: "яндекс плюс"},
+		{payee: "  Apple   Music ", want: "apple music"},
+		{payee: "1234 5678", want: ""},
+		{payee: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payee, func(t *testing.T) {
+			assert.Equal(t, tt.want, MerchantKey(tt.payee))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		txs  []Transaction
+		want []Recurring
+	}{
+		{
+			// Даты списаний сдвигаются на день, доход и разовая покупка не мешают
+			name: "monthly",
+			txs: []Transaction{
+				debit("2025-01-05", 79900, "NETFLIX.COM 866-579-7172"),
+				debit("2025-02-05", 79900, "NETFLIX.COM 866-579-7172"),
+				debit("2025-02-10", 150000, "Grocery store"),
+				debit("2025-03-06", 79900, "NETFLIX.COM 866-579-7172"),
+				{Date: day("2025-03-10"), Amount: 79900, Currency: "RUB", Payee: "Netflix.com refund"},
+				debit("2025-04-05", 79900, "Netflix.com"),
+				debit("2025-04-20", 30000, "Grocery store"),
+			},
+			want: []Recurring{{
+				Name: "Netflix", Merchant: "Netflix.com", Amount: 79900, Currency: "RUB", Period: model.BillingMonth,
+				Charges: []time.Time{day("2025-01-05"), day("2025-02-05"), day("2025-03-06"), day("2025-04-05")},
+			}},
+		},
+		{
+			name: "price increase stays in series",
+			txs: []Transaction{
+				debit("2025-01-10", 29900, "Spotify"),
+				debit("2025-02-10", 29900, "Spotify"),
+				debit("2025-03-10", 34900, "Spotify"),
+			},
+			want: []Recurring{{
+				Name: "Spotify", Merchant: "Spotify", Amount: 34900, Currency: "RUB", Period: model.BillingMonth,
+				Charges: []time.Time{day("2025-01-10"), day("2025-02-10"), day("2025-03-10")},
+			}},
+		},
+		{
+			name: "tariffs of one merchant are separate",
+			txs: []Transaction{
+				debit("2025-01-01", 9900, "Apple"),
+				debit("2025-01-15", 99900, "Apple"),
+				debit("2025-02-01", 9900, "Apple"),
+				debit("2025-02-15", 99900, "Apple"),
+				debit("2025-03-01", 9900, "Apple"),
+				debit("2025-03-15", 99900, "Apple"),
+			},
+			want: []Recurring{
+				{
+					Name: "Apple", Merchant: "Apple", Amount: 9900, Currency: "RUB", Period: model.BillingMonth,
+					Charges: []time.Time{day("2025-01-01"), day("2025-02-01"), day("2025-03-01")},
+				},
+				{
+					Name: "Apple", Merchant: "Apple", Amount: 99900, Currency: "RUB", Period: model.BillingMonth,
+					Charges: []time.Time{day("2025-01-15"), day("2025-02-15"), day("2025-03-15")},
+				},
+			},
+		},
+		{
+			name: "weekly and yearly",
+			txs: []Transaction{
+				debit("2024-03-01", 199000, "Domain registrar"),
+				debit("2025-03-01", 199000, "Domain registrar"),
+				debit("2025-03-03", 5000, "Car wash"),
+				debit("2025-03-10", 5000, "Car wash"),
+				debit("2025-03-17", 5000, "Car wash"),
+				debit("2025-03-24", 5000, "Car wash"),
+			},
+			want: []Recurring{
+				{
+					Name: "Car Wash", Merchant: "Car wash", Amount: 5000, Currency: "RUB", Period: model.BillingWeek,
+					Charges: []time.Time{day("2025-03-03"), day("2025-03-10"), day("2025-03-17"), day("2025-03-24")},
+				},
+				{
+					Name: "Domain Registrar", Merchant: "Domain registrar", Amount: 199000, Currency: "RUB", Period: model.BillingYear,
+					Charges: []time.Time{day("2024-03-01"), day("2025-03-01")},
+				},
+			},
+		},
+		{
+			name: "ended before statement end",
+			txs: []Transaction{
+				debit("2025-01-10", 19900, "Okko"),
+				debit("2025-02-10", 19900, "Okko"),
+				debit("2025-03-10", 19900, "Okko"),
+				debit("2025-07-01", 100000, "Grocery store"),
+			},
+			want: []Recurring{{
+				Name: "Okko", Merchant: "Okko", Amount: 19900, Currency: "RUB", Period: model.BillingMonth,
+				Charges: []time.Time{day("2025-01-10"), day("2025-02-10"), day("2025-03-10")}, Ended: true,
+			}},
+		},
+		{
+			name: "last regular run after a gap",
+			txs: []Transaction{
+				debit("2024-01-10", 19900, "Okko"),
+				debit("2024-02-10", 19900, "Okko"),
+				debit("2024-03-10", 19900, "Okko"),
+				debit("2024-09-10", 19900, "Okko"),
+				debit("2024-10-10", 19900, "Okko"),
+				debit("2024-11-10", 19900, "Okko"),
+			},
+			want: []Recurring{{
+				Name: "Okko", Merchant: "Okko", Amount: 19900, Currency: "RUB", Period: model.BillingMonth,
+				Charges: []time.Time{day("2024-09-10"), day("2024-10-10"), day("2024-11-10")},
+			}},
+		},
+		{
+			name: "too few charges",
+			txs: []Transaction{
+				debit("2025-01-10", 19900, "Okko"),
+				debit("2025-02-10", 19900, "Okko"),
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Detect(tt.txs))
+		})
+	}
+}
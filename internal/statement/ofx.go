@@ -0,0 +1,119 @@
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var ofxEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&apos;", "'", "&quot;", `"`)
+
+// ofxTransaction поля операции STMTTRN до разбора.
+type ofxTransaction struct {
+	posted, amount, name, memo, currency string
+}
+
+// ParseOFX читает операции банковских и карточных выписок OFX 1.x (SGML, теги
+// без закрытия) и OFX 2.x (XML). Валюта операции берётся из CURRENCY/ORIGCURRENCY,
+// затем из CURDEF выписки, затем currency.
+func ParseOFX(r io.Reader, currency string) ([]Transaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, errors.New("invalid OFX: no <OFX> element")
+	}
+	data = data[start:]
+
+	var (
+		txs     []Transaction
+		cur     *ofxTransaction
+		curDef  string
+		element string
+	)
+	for len(data) > 0 {
+		open := bytes.IndexByte(data, '<')
+		if open < 0 {
+			break
+		}
+		text := strings.TrimSpace(ofxEntities.Replace(string(data[:open])))
+		if text != "" && cur != nil {
+			switch element {
+			case "DTPOSTED":
+				cur.posted = text
+			case "TRNAMT":
+				cur.amount = text
+			case "NAME":
+				cur.name = text
+			case "MEMO":
+				cur.memo = text
+			case "CURSYM":
+				cur.currency = text
+			}
+		} else if text != "" && element == "CURDEF" {
+			curDef = text
+		}
+
+		data = data[open:]
+		end := bytes.IndexByte(data, '>')
+		if end < 0 {
+			return nil, errors.New("invalid OFX: unterminated tag")
+		}
+		tag := strings.ToUpper(strings.TrimSpace(string(data[1:end])))
+		data = data[end+1:]
+		if strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+
+		element = tag
+		switch tag {
+		case "STMTTRN":
+			cur = &ofxTransaction{}
+		case "/STMTTRN":
+			if cur == nil {
+				return nil, errors.New("invalid OFX: unexpected </STMTTRN>")
+			}
+			tx, err := cur.parse(firstNonEmpty(curDef, currency))
+			if err != nil {
+				return nil, fmt.Errorf("invalid OFX: transaction %d: %w", len(txs)+1, err)
+			}
+			txs = append(txs, tx)
+			cur = nil
+		}
+	}
+	return txs, nil
+}
+
+func (t *ofxTransaction) parse(currency string) (Transaction, error) {
+	currency = strings.ToUpper(firstNonEmpty(t.currency, currency))
+	tx := Transaction{Currency: currency, Payee: firstNonEmpty(t.name, t.memo)}
+
+	// DTPOSTED — YYYYMMDD, дальше может идти время и часовой пояс: важен только день
+	if len(t.posted) < 8 {
+		return tx, fmt.Errorf("invalid DTPOSTED %q", t.posted)
+	}
+	date, err := time.Parse("20060102", t.posted[:8])
+	if err != nil {
+		return tx, fmt.Errorf("invalid DTPOSTED %q", t.posted)
+	}
+	tx.Date = date
+
+	if tx.Amount, err = parseAmount(t.amount, currency); err != nil {
+		return tx, fmt.Errorf("invalid TRNAMT: %w", err)
+	}
+	return tx, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
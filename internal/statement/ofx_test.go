@@ -0,0 +1,90 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		currency string
+		want     []Transaction
+		wantErr  string
+	}{
+		{
+			name: "sgml",
+			data: `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250105120000[-5:EST]
+<TRNAMT>-15.49
+<NAME>NETFLIX.COM
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250107
+<TRNAMT>-9.99
+<MEMO>Tom &amp; Jerry
+<CURRENCY><CURRATE>1.1<CURSYM>EUR</CURRENCY>
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`,
+			want: []Transaction{
+				{Date: day("2025-01-05"), Amount: -1549, Currency: "USD", Payee: "NETFLIX.COM"},
+				{Date: day("2025-01-07"), Amount: -999, Currency: "EUR", Payee: "Tom & Jerry"},
+			},
+		},
+		{
+			name: "xml without currency",
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><BANKTRANLIST>
+<STMTTRN><DTPOSTED>20250210</DTPOSTED><TRNAMT>-799.00</TRNAMT><NAME>Okko</NAME></STMTTRN>
+</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1></OFX>`,
+			currency: "rub",
+			want: []Transaction{
+				{Date: day("2025-02-10"), Amount: -79900, Currency: "RUB", Payee: "Okko"},
+			},
+		},
+		{
+			name:    "no ofx element",
+			data:    "date,amount,payee\n",
+			wantErr: "invalid OFX: no <OFX> element",
+		},
+		{
+			name:    "invalid date",
+			data:    "<OFX><STMTTRN><DTPOSTED>2025<TRNAMT>-1<NAME>X</STMTTRN></OFX>",
+			wantErr: `invalid OFX: transaction 1: invalid DTPOSTED "2025"`,
+		},
+		{
+			name:    "unterminated tag",
+			data:    "<OFX><STMTTRN",
+			wantErr: "invalid OFX: unterminated tag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txs, err := ParseOFX(strings.NewReader(tt.data), tt.currency)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, txs)
+		})
+	}
+}
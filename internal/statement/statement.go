@@ -0,0 +1,110 @@
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"subscriptions/internal/exchange"
+)
+
+// Format формат банковской выписки.
+type Format string
+
+const (
+	OFX Format = "ofx"
+	CSV Format = "csv"
+)
+
+var ErrUnknownFormat = errors.New("unknown statement format")
+
+// Transaction операция из выписки. Amount — в минимальных единицах валюты,
+// списания отрицательные. Пустая Currency — валюта в выписке не указана.
+type Transaction struct {
+	Date     time.Time
+	Amount   int
+	Currency string
+	Payee    string
+}
+
+// DetectFormat определяет формат выписки по началу файла: OFX 1.x начинается
+// с заголовка OFXHEADER, OFX 2.x — с XML-пролога, всё остальное считается CSV.
+func DetectFormat(data []byte) Format {
+	head := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	upper := bytes.ToUpper(head)
+	if bytes.HasPrefix(upper, []byte("OFXHEADER")) || bytes.Contains(upper, []byte("<OFX>")) ||
+		(bytes.HasPrefix(upper, []byte("<?XML")) && bytes.Contains(upper, []byte("OFX"))) {
+		return OFX
+	}
+	return CSV
+}
+
+// parseAmount переводит десятичную сумму в минимальные единицы валюты currency.
+// Понимает знак в начале или в конце, сумму в скобках, пробелы между разрядами
+// и запятую или точку как десятичный разделитель.
+func parseAmount(s, currency string) (int, error) {
+	v := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, s)
+
+	negative := false
+	switch {
+	case strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")"):
+		negative, v = true, v[1:len(v)-1]
+	case strings.HasPrefix(v, "-"), strings.HasPrefix(v, "−"):
+		negative, v = true, strings.TrimLeft(v, "-−")
+	case strings.HasSuffix(v, "-"):
+		negative, v = true, strings.TrimSuffix(v, "-")
+	case strings.HasPrefix(v, "+"):
+		v = v[1:]
+	}
+
+	digits := exchange.MinorUnits(currency)
+	// Десятичный разделитель — последний из встреченных, если перед ним есть разделитель
+	// другого вида или он единственный и после него не три цифры (три цифры бывают
+	// только у валют с тремя знаками); остальные разделители отделяют разряды.
+	whole, frac := v, ""
+	if i := strings.LastIndexAny(v, ".,"); i >= 0 {
+		other := "."
+		if v[i] == '.' {
+			other = ","
+		}
+		single := strings.Count(v, v[i:i+1]) == 1
+		if strings.Contains(v[:i], other) || (single && (len(v)-i-1 != 3 || digits == 3)) {
+			whole, frac = v[:i], v[i+1:]
+		}
+	}
+	whole = strings.NewReplacer(",", "", ".", "").Replace(whole)
+
+	if whole == "" || len(frac) > digits || !allDigits(whole) || !allDigits(frac) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+	amount, err := strconv.Atoi(whole + frac)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package statement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     int
+		wantErr  bool
+	}{
+		{in: "-123.45", currency: "RUB", want: -12345},
+		{in: "123,45", currency: "RUB", want: 12345},
+		{in: "+5", currency: "RUB", want: 500},
+		{in: "(99.90)", currency: "USD", want: -9990},
+		{in: "12.50-", currency: "USD", want: -1250},
+		{in: "−42", currency: "RUB", want: -4200},
+		{in: "1 234,56", currency: "RUB", want: 123456},
+		{in: "1\u00a0234,56", currency: "RUB", want: 123456},
+		{in: "1'234.50", currency: "CHF", want: 123450},
+		{in: "1,234.56", currency: "USD", want: 123456},
+		{in: "1.234,56", currency: "EUR", want: 123456},
+		{in: "1,234", currency: "USD", want: 123400},
+		{in: "12.345", currency: "USD", want: 1234500},
+		{in: "1.5", currency: "RUB", want: 150},
+		{in: "1.500", currency: "KWD", want: 1500},
+		{in: "1500", currency: "JPY", want: 1500},
+		{in: "1,500", currency: "JPY", want: 1500},
+		{in: "12.5", currency: "JPY", wantErr: true},
+		{in: "1.234", currency: "", want: 123400},
+		{in: "1.234.567", currency: "RUB", want: 123456700},
+		{in: "12.345,6", currency: "RUB", want: 1234560},
+		{in: "abc", currency: "RUB", wantErr: true},
+		{in: "", currency: "RUB", wantErr: true},
+		{in: "-", currency: "RUB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in+" "+tt.currency, func(t *testing.T) {
+			got, err := parseAmount(tt.in, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Format
+	}{
+		{name: "ofx 1.x", data: "OFXHEADER:100\nDATA:OFXSGML\n\n<OFX>", want: OFX},
+		{name: "ofx 2.x", data: `<?xml version="1.0"?><?OFX OFXHEADER="200"?><OFX>`, want: OFX},
+		{name: "bom and spaces", data: "\ufeff  \n<ofx>", want: OFX},
+		{name: "csv", data: "date,amount,payee\n2025-01-01,-1,Netflix", want: CSV},
+		{name: "other xml", data: `<?xml version="1.0"?><rss>`, want: CSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectFormat([]byte(tt.data)))
+		})
+	}
+}
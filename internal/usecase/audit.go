@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+// AnonymousActor записывается в журнал, если автор изменения неизвестен.
+const AnonymousActor = "anonymous"
+
+const maxAuditLimit = 1000
+
+type actorKey struct{}
+
+// WithActor возвращает контекст, в котором изменения подписок записываются в журнал от имени actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom возвращает автора изменений из контекста или AnonymousActor.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// ListAuditRecords возвращает записи журнала изменений по возрастанию времени.
+func (s *Usecase) ListAuditRecords(ctx context.Context, filter model.AuditFilter, limit, offset int) (*model.AuditList, error) {
+	if limit > maxAuditLimit {
+		return nil, NewValidationError("limit", fmt.Sprintf("limit must not exceed %d", maxAuditLimit))
+	}
+	if filter.Operation != nil && !filter.Operation.Valid() {
+		return nil, NewValidationError("operation", "operation must be one of create, update, delete, restore, cancel, purge, price_change, price_change_delete, pause, resume")
+	}
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return nil, NewValidationError("since", "since must be before until")
+	}
+	return s.repo.ListAuditRecords(ctx, filter, limit, offset)
+}
+
+// SubscriptionHistory возвращает все изменения подписки. История доступна и после
+// удаления подписки, пока в журнале есть записи о ней.
+func (s *Usecase) SubscriptionHistory(ctx context.Context, id uuid.UUID) ([]model.AuditRecord, error) {
+	list, err := s.repo.ListAuditRecords(ctx, model.AuditFilter{SubscriptionID: &id}, -1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		// Подписки, созданные до появления журнала, имеют пустую историю
+		if _, err := s.GetSubscription(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return list.Items, nil
+}
+
+// audit записывает изменение подписки в журнал. before == nil для созданной подписки,
+// after == nil для окончательно удалённой.
+func (s *Usecase) audit(ctx context.Context, op model.AuditOperation, before, after *model.Subscription) error {
+	changes, err := diffSubscriptions(before, after)
+	if err != nil {
+		return fmt.Errorf("audit %s: %w", op, err)
+	}
+	sub := after
+	if sub == nil {
+		sub = before
+	}
+	return s.writeAudit(ctx, op, sub, changes)
+}
+
+// auditRelated записывает в журнал изменение записи, связанной с подпиской sub, — цены
+// или паузы — под ключом field. before == nil для новой записи, after == nil для удалённой.
+func (s *Usecase) auditRelated(ctx context.Context, op model.AuditOperation, sub *model.Subscription, field string, before, after any) error {
+	from, err := toValue(before)
+	if err != nil {
+		return fmt.Errorf("audit %s: %w", op, err)
+	}
+	to, err := toValue(after)
+	if err != nil {
+		return fmt.Errorf("audit %s: %w", op, err)
+	}
+	return s.writeAudit(ctx, op, sub, map[string]model.FieldChange{field: {Before: from, After: to}})
+}
+
+func (s *Usecase) writeAudit(ctx context.Context, op model.AuditOperation, sub *model.Subscription, changes map[string]model.FieldChange) error {
+	rec := &model.AuditRecord{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		Actor:          ActorFrom(ctx),
+		Operation:      op,
+		Changes:        changes,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.repo.CreateAuditRecord(ctx, rec); err != nil {
+		return fmt.Errorf("audit %s: %w", op, err)
+	}
+	return nil
+}
+
+// diffSubscriptions сравнивает JSON-представления подписок и возвращает изменённые поля.
+func diffSubscriptions(before, after *model.Subscription) (map[string]model.FieldChange, error) {
+	from, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]model.FieldChange)
+	for field, value := range to {
+		if old, ok := from[field]; !ok || !reflect.DeepEqual(old, value) {
+			changes[field] = model.FieldChange{Before: from[field], After: value}
+		}
+	}
+	for field, old := range from {
+		if _, ok := to[field]; !ok {
+			changes[field] = model.FieldChange{Before: old}
+		}
+	}
+	return changes, nil
+}
+
+func toFields(sub *model.Subscription) (map[string]any, error) {
+	fields := map[string]any{}
+	if sub == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	// null и отсутствующее поле для журнала равнозначны
+	for field, value := range fields {
+		if value == nil {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// toValue приводит значение к JSON-представлению, в котором оно хранится в журнале.
+func toValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
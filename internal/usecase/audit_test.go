@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+var errAuditUnavailable = errors.New("audit log unavailable")
+
+// failingAudit репозиторий, в котором запись в журнал всегда завершается ошибкой,
+// в том числе внутри транзакции.
+type failingAudit struct {
+	repository.Repository
+}
+
+func (r failingAudit) Transaction(ctx context.Context, fn func(tx repository.Repository) error) error {
+	return r.Repository.Transaction(ctx, func(tx repository.Repository) error {
+		return fn(failingAudit{tx})
+	})
+}
+
+func (failingAudit) CreateAuditRecord(context.Context, *model.AuditRecord) error {
+	return errAuditUnavailable
+}
+
+func TestDiffSubscriptions(t *testing.T) {
+	before := validSubscription()
+	after := *before
+	after.Price = 99900
+	after.EndDate = ptr(month(2025, 6))
+
+	changes, err := diffSubscriptions(before, &after)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.FieldChange{
+		"price":    {Before: float64(79900), After: float64(99900)},
+		"end_date": {After: "2025-06-01T00:00:00Z"},
+	}, changes)
+
+	changes, err = diffSubscriptions(nil, before)
+	require.NoError(t, err)
+	assert.Equal(t, "Netflix", changes["service_name"].After)
+	assert.Nil(t, changes["service_name"].Before)
+}
+
+func TestAuditOperations(t *testing.T) {
+	ctx := WithActor(context.Background(), "alice")
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	sub := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+
+	steps := []struct {
+		op     model.AuditOperation
+		mutate func() error
+		field  string
+	}{
+		{op: model.AuditCreate, mutate: func() error { return nil }, field: "service_name"},
+		{op: model.AuditUpdate, mutate: func() error {
+			sub.Price = 99900
+			return s.UpdateSubscription(ctx, sub)
+		}, field: "price"},
+		{op: model.AuditPriceChange, mutate: func() error {
+			return s.SchedulePriceChange(ctx, &model.PriceChange{SubscriptionID: sub.ID, Price: 120000, EffectiveFrom: month(2025, 6)})
+		}, field: "price_change"},
+		{op: model.AuditPriceChangeDelete, mutate: func() error {
+			changes, err := s.ListPriceChanges(ctx, sub.ID)
+			require.NoError(t, err)
+			return s.DeletePriceChange(ctx, sub.ID, changes[0].ID)
+		}, field: "price_change"},
+		{op: model.AuditPause, mutate: func() error {
+			_, err := s.PauseSubscription(ctx, sub.ID, ptr(month(2025, 3)))
+			return err
+		}, field: "pause"},
+		{op: model.AuditResume, mutate: func() error {
+			_, err := s.ResumeSubscription(ctx, sub.ID, ptr(month(2025, 5)))
+			return err
+		}, field: "pause"},
+		{op: model.AuditCancel, mutate: func() error {
+			_, err := s.CancelSubscription(ctx, sub.ID, ptr(month(2025, 8)), model.CancelNotUsing)
+			return err
+		}, field: "canceled_at"},
+		{op: model.AuditDelete, mutate: func() error { return s.DeleteSubscription(ctx, sub.ID) }, field: "deleted_at"},
+		{op: model.AuditRestore, mutate: func() error {
+			_, err := s.RestoreSubscription(ctx, sub.ID)
+			return err
+		}, field: "deleted_at"},
+		{op: model.AuditPurge, mutate: func() error {
+			if err := s.DeleteSubscription(ctx, sub.ID); err != nil {
+				return err
+			}
+			_, err := s.PurgeDeleted(ctx, -time.Hour)
+			return err
+		}, field: "service_name"},
+	}
+
+	for _, step := range steps {
+		t.Run(string(step.op), func(t *testing.T) {
+			require.NoError(t, step.mutate())
+			list, err := s.ListAuditRecords(ctx, model.AuditFilter{SubscriptionID: &sub.ID, Operation: &step.op}, -1, 0)
+			require.NoError(t, err)
+			require.Len(t, list.Items, 1)
+			rec := list.Items[0]
+			assert.Equal(t, "alice", rec.Actor)
+			assert.Equal(t, sub.UserID, rec.UserID)
+			assert.Contains(t, rec.Changes, step.field)
+		})
+	}
+
+	// История переживает окончательное удаление подписки
+	history, err := s.SubscriptionHistory(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, len(steps)+1, "purge is preceded by one more delete")
+}
+
+func TestAuditRollback(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	s := New(repo, nil, nil, nil)
+	failing := New(failingAudit{repo}, nil, nil, nil)
+
+	sub := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	paused := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, paused, true))
+	_, err := s.PauseSubscription(ctx, paused.ID, ptr(month(2025, 3)))
+	require.NoError(t, err)
+	priced := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, priced, true))
+	pc := &model.PriceChange{SubscriptionID: priced.ID, Price: 120000, EffectiveFrom: month(2025, 6)}
+	require.NoError(t, s.SchedulePriceChange(ctx, pc))
+	deleted := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, deleted, true))
+	require.NoError(t, s.DeleteSubscription(ctx, deleted.ID))
+
+	tests := []struct {
+		name   string
+		mutate func() error
+		check  func(t *testing.T)
+	}{
+		{
+			name: "create",
+			mutate: func() error {
+				created := validSubscription()
+				created.ServiceName = "Spotify"
+				return failing.CreateSubscription(ctx, created, false)
+			},
+			check: func(t *testing.T) {
+				list, err := s.ListSubscriptions(ctx, model.SubscriptionFilter{ServiceName: ptr("Spotify")}, -1, 0)
+				require.NoError(t, err)
+				assert.Empty(t, list.Items)
+			},
+		},
+		{
+			name: "update",
+			mutate: func() error {
+				changed := *sub
+				changed.Price = 1
+				return failing.UpdateSubscription(ctx, &changed)
+			},
+			check: func(t *testing.T) {
+				got, err := s.GetSubscription(ctx, sub.ID)
+				require.NoError(t, err)
+				assert.Equal(t, sub.Price, got.Price)
+			},
+		},
+		{
+			name: "cancel",
+			mutate: func() error {
+				_, err := failing.CancelSubscription(ctx, sub.ID, nil, "")
+				return err
+			},
+			check: func(t *testing.T) {
+				got, err := s.GetSubscription(ctx, sub.ID)
+				require.NoError(t, err)
+				assert.Nil(t, got.CanceledAt)
+			},
+		},
+		{
+			name:   "delete",
+			mutate: func() error { return failing.DeleteSubscription(ctx, sub.ID) },
+			check: func(t *testing.T) {
+				_, err := s.GetSubscription(ctx, sub.ID)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "restore",
+			mutate: func() error {
+				_, err := failing.RestoreSubscription(ctx, deleted.ID)
+				return err
+			},
+			check: func(t *testing.T) {
+				_, err := s.GetSubscription(ctx, deleted.ID)
+				assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+			},
+		},
+		{
+			name: "purge",
+			mutate: func() error {
+				_, err := failing.PurgeDeleted(ctx, -time.Hour)
+				return err
+			},
+			check: func(t *testing.T) {
+				_, err := s.RestoreSubscription(ctx, deleted.ID)
+				require.NoError(t, err)
+				require.NoError(t, s.DeleteSubscription(ctx, deleted.ID))
+			},
+		},
+		{
+			name: "schedule price change",
+			mutate: func() error {
+				return failing.SchedulePriceChange(ctx, &model.PriceChange{SubscriptionID: sub.ID, Price: 120000, EffectiveFrom: month(2025, 6)})
+			},
+			check: func(t *testing.T) {
+				changes, err := s.ListPriceChanges(ctx, sub.ID)
+				require.NoError(t, err)
+				assert.Empty(t, changes)
+			},
+		},
+		{
+			name:   "delete price change",
+			mutate: func() error { return failing.DeletePriceChange(ctx, priced.ID, pc.ID) },
+			check: func(t *testing.T) {
+				changes, err := s.ListPriceChanges(ctx, priced.ID)
+				require.NoError(t, err)
+				assert.Len(t, changes, 1)
+			},
+		},
+		{
+			name: "pause",
+			mutate: func() error {
+				_, err := failing.PauseSubscription(ctx, sub.ID, ptr(month(2025, 3)))
+				return err
+			},
+			check: func(t *testing.T) {
+				pauses, err := s.ListPauses(ctx, sub.ID)
+				require.NoError(t, err)
+				assert.Empty(t, pauses)
+			},
+		},
+		{
+			name: "resume",
+			mutate: func() error {
+				_, err := failing.ResumeSubscription(ctx, paused.ID, ptr(month(2025, 5)))
+				return err
+			},
+			check: func(t *testing.T) {
+				pauses, err := s.ListPauses(ctx, paused.ID)
+				require.NoError(t, err)
+				require.Len(t, pauses, 1)
+				assert.Nil(t, pauses[0].ResumedAt)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.mutate(), errAuditUnavailable)
+			tt.check(t)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+// maxBatchSize ограничивает число операций в одном пакете
+const maxBatchSize = 500
+
+// BatchOperation операция пакета. Subscription нужна для создания и изменения,
+// ID — для изменения и удаления. Err — ошибка разбора запроса: такая операция
+// не выполняется и сразу считается неудачной.
+type BatchOperation struct {
+	Op           model.BatchOp
+	ID           uuid.UUID
+	Subscription *model.Subscription
+	Err          error
+}
+
+// BatchResult итог одной операции пакета: сохранённая подписка (кроме удаления) или ошибка.
+type BatchResult struct {
+	Subscription *model.Subscription
+	Err          error
+}
+
+// BatchError ошибка операции с номером Index, из-за которой отменён атомарный пакет.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyBatch выполняет операции по порядку с теми же проверками и записью в журнал,
+// что и одиночные запросы. В режиме BatchAtomic все операции выполняются в одной
+// транзакции, и первая ошибка отменяет пакет и возвращается как *BatchError.
+// В режиме BatchBestEffort каждая операция выполняется в своей транзакции,
+// а её ошибка попадает в результат.
+func (s *Usecase) ApplyBatch(ctx context.Context, ops []BatchOperation, mode model.BatchMode) ([]BatchResult, error) {
+	if len(ops) == 0 {
+		return nil, NewValidationError("operations", "operations must not be empty")
+	}
+	if len(ops) > maxBatchSize {
+		return nil, NewValidationError("operations", fmt.Sprintf("at most %d operations are allowed", maxBatchSize))
+	}
+	if !mode.Valid() {
+		return nil, NewValidationError("mode", "mode must be one of atomic, best_effort")
+	}
+
+	results := make([]BatchResult, len(ops))
+	if mode == model.BatchAtomic {
+		// Заведомо неудачный пакет не открывает транзакцию
+		for i, op := range ops {
+			if op.Err != nil {
+				return nil, &BatchError{Index: i, Err: op.Err}
+			}
+		}
+		err := s.inTransaction(ctx, func(tx *Usecase) error {
+			for i, op := range ops {
+				sub, err := tx.applyOperation(ctx, op)
+				if err != nil {
+					return &BatchError{Index: i, Err: err}
+				}
+				results[i].Subscription = sub
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	for i, op := range ops {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Отдельная транзакция не даёт операции сохраниться без записи в журнал
+		err := s.inTransaction(ctx, func(tx *Usecase) error {
+			sub, err := tx.applyOperation(ctx, op)
+			results[i].Subscription = sub
+			return err
+		})
+		if err != nil {
+			results[i] = BatchResult{Err: err}
+		}
+	}
+	return results, nil
+}
+
+func (s *Usecase) applyOperation(ctx context.Context, op BatchOperation) (*model.Subscription, error) {
+	if op.Err != nil {
+		return nil, op.Err
+	}
+	if !op.Op.Valid() {
+		return nil, NewValidationError("op", "op must be one of create, update, delete")
+	}
+	if op.Op != model.BatchCreate && op.ID == uuid.Nil {
+		return nil, NewValidationError("id", "id is required for update and delete")
+	}
+	if op.Op != model.BatchDelete && op.Subscription == nil {
+		return nil, NewValidationError("subscription", "subscription is required for create and update")
+	}
+
+	switch op.Op {
+	case model.BatchCreate:
+		// Пакет создаёт ровно то, что в нём перечислено, поэтому дубликаты не проверяются
+		if err := s.CreateSubscription(ctx, op.Subscription, true); err != nil {
+			return nil, err
+		}
+		return op.Subscription, nil
+	case model.BatchUpdate:
+		op.Subscription.ID = op.ID
+		if err := s.UpdateSubscription(ctx, op.Subscription); err != nil {
+			return nil, err
+		}
+		return op.Subscription, nil
+	default:
+		return nil, s.DeleteSubscription(ctx, op.ID)
+	}
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestApplyBatchValidation(t *testing.T) {
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	create := BatchOperation{Op: model.BatchCreate, Subscription: validSubscription()}
+
+	tests := []struct {
+		name      string
+		ops       []BatchOperation
+		mode      model.BatchMode
+		wantField string
+	}{
+		{name: "empty", mode: model.BatchAtomic, wantField: "operations"},
+		{name: "too many", ops: make([]BatchOperation, maxBatchSize+1), mode: model.BatchAtomic, wantField: "operations"},
+		{name: "unknown mode", ops: []BatchOperation{create}, mode: "eventually", wantField: "mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ApplyBatch(context.Background(), tt.ops, tt.mode)
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestApplyBatch(t *testing.T) {
+	ctx := context.Background()
+	missing := uuid.New()
+
+	tests := []struct {
+		name string
+		mode model.BatchMode
+		// withUnparsed добавляет в конец операцию с ошибкой разбора запроса
+		withUnparsed bool
+		wantIndex    int
+		wantErr      error
+		wantKept     int
+	}{
+		// Третья операция обращается к несуществующей подписке
+		{name: "atomic rolls back", mode: model.BatchAtomic, wantIndex: 2, wantErr: ErrSubscriptionNotFound},
+		// Пакет с ошибкой разбора отклоняется до выполнения операций
+		{name: "atomic with unparsed operation", mode: model.BatchAtomic, withUnparsed: true, wantIndex: 3, wantErr: ErrValidation},
+		{name: "best effort keeps the rest", mode: model.BatchBestEffort, withUnparsed: true, wantIndex: -1, wantKept: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(repository.NewMemoryRepository(), nil, nil, nil)
+			existing := validSubscription()
+			require.NoError(t, s.CreateSubscription(ctx, existing, false))
+
+			created := validSubscription()
+			created.ServiceName = "Spotify"
+			updated := validSubscription()
+			updated.Price = 99900
+			ops := []BatchOperation{
+				{Op: model.BatchCreate, Subscription: created},
+				{Op: model.BatchDelete, ID: existing.ID},
+				{Op: model.BatchUpdate, ID: missing, Subscription: updated},
+			}
+			if tt.withUnparsed {
+				ops = append(ops, BatchOperation{Op: model.BatchCreate, Err: NewValidationError("start_date", "start_date must be in MM-YYYY format")})
+			}
+
+			results, err := s.ApplyBatch(ctx, ops, tt.mode)
+			list, listErr := s.ListSubscriptions(ctx, model.SubscriptionFilter{ServiceName: ptr("spotify")}, -1, 0)
+			require.NoError(t, listErr)
+			assert.Len(t, list.Items, tt.wantKept)
+
+			if tt.wantIndex >= 0 {
+				var batchErr *BatchError
+				require.True(t, errors.As(err, &batchErr), "got %v", err)
+				assert.Equal(t, tt.wantIndex, batchErr.Index)
+				assert.ErrorIs(t, err, tt.wantErr)
+				_, err := s.GetSubscription(ctx, existing.ID)
+				assert.NoError(t, err, "deletion is rolled back")
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, results, len(ops))
+			assert.NoError(t, results[0].Err)
+			assert.Equal(t, created.ID, results[0].Subscription.ID)
+			assert.NoError(t, results[1].Err)
+			assert.ErrorIs(t, results[2].Err, ErrSubscriptionNotFound)
+			assert.ErrorIs(t, results[3].Err, ErrValidation)
+			_, err = s.GetSubscription(ctx, existing.ID)
+			assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+		})
+	}
+}
+
+func TestApplyBatchDefersAlerts(t *testing.T) {
+	ctx := context.Background()
+	var alerts []model.BudgetAlert
+	notifier := BudgetNotifierFunc(func(_ context.Context, alert model.BudgetAlert) {
+		alerts = append(alerts, alert)
+	})
+	s := New(repository.NewMemoryRepository(), nil, notifier, nil)
+	userID := uuid.New()
+	require.NoError(t, s.CreateBudget(ctx, &model.Budget{UserID: userID, Amount: 1000}))
+
+	expensive := validSubscription()
+	expensive.UserID = userID
+	expensive.StartDate = currentMonth().AddDate(0, 1, 0)
+	ops := []BatchOperation{
+		{Op: model.BatchCreate, Subscription: expensive},
+		{Op: model.BatchDelete, ID: uuid.New()},
+	}
+
+	// Откат пакета не оставляет оповещений о превышении бюджета
+	_, err := s.ApplyBatch(ctx, ops, model.BatchAtomic)
+	require.Error(t, err)
+	assert.Empty(t, alerts)
+
+	expensive.ID = uuid.Nil
+	_, err = s.ApplyBatch(ctx, ops[:1], model.BatchAtomic)
+	require.NoError(t, err)
+	assert.Len(t, alerts, 1)
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestCalculateBreakdown(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), rubRates{"RUB": 1, "USD": 80}, nil, nil)
+
+	// Один сервис оплачивается в двух валютах
+	for _, currency := range []string{"RUB", "USD"} {
+		sub := validSubscription()
+		sub.Price = 1000
+		sub.Currency = currency
+		sub.EndDate = ptr(month(2025, 2))
+		require.NoError(t, s.CreateSubscription(ctx, sub, true))
+	}
+
+	tests := []struct {
+		name      string
+		currency  string
+		want      []model.BreakdownItem
+		wantField string
+	}{
+		{
+			name: "by currency",
+			want: []model.BreakdownItem{
+				{Month: month(2025, 1), ServiceName: "Netflix", Amount: 1000, Currency: "RUB"},
+				{Month: month(2025, 1), ServiceName: "Netflix", Amount: 1000, Currency: "USD"},
+				{Month: month(2025, 2), ServiceName: "Netflix", Amount: 1000, Currency: "RUB"},
+				{Month: month(2025, 2), ServiceName: "Netflix", Amount: 1000, Currency: "USD"},
+			},
+		},
+		{
+			name:     "converted and merged",
+			currency: "rub",
+			want: []model.BreakdownItem{
+				{Month: month(2025, 1), ServiceName: "Netflix", Amount: 1000 + 80000, Currency: "RUB"},
+				{Month: month(2025, 2), ServiceName: "Netflix", Amount: 1000 + 80000, Currency: "RUB"},
+			},
+		},
+		{name: "invalid currency", currency: "рубль", wantField: "currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := s.CalculateBreakdown(ctx, model.SubscriptionFilter{}, month(2025, 1), month(2025, 3), tt.currency)
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, items)
+		})
+	}
+
+	_, err := s.CalculateBreakdown(ctx, model.SubscriptionFilter{}, month(2025, 3), month(2025, 1), "")
+	assert.ErrorIs(t, err, ErrValidation)
+}
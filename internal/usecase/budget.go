@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/exchange"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+var (
+	ErrBudgetNotFound = fmt.Errorf("budget %w", ErrNotFound)
+	ErrBudgetExists   = newError(ErrConflict, "budget for this category already exists")
+)
+
+// BudgetNotifier получает события о превышении бюджета. Вызывается синхронно
+// после сохранения подписки, поэтому реализация не должна надолго блокироваться.
+type BudgetNotifier interface {
+	BudgetExceeded(ctx context.Context, alert model.BudgetAlert)
+}
+
+// BudgetNotifierFunc позволяет использовать функцию как BudgetNotifier.
+type BudgetNotifierFunc func(ctx context.Context, alert model.BudgetAlert)
+
+func (f BudgetNotifierFunc) BudgetExceeded(ctx context.Context, alert model.BudgetAlert) {
+	f(ctx, alert)
+}
+
+func (s *Usecase) CreateBudget(ctx context.Context, b *model.Budget) error {
+	if err := validateBudget(b); err != nil {
+		return err
+	}
+	return mapBudgetErr(s.repo.CreateBudget(ctx, b))
+}
+
+func (s *Usecase) GetBudget(ctx context.Context, id uuid.UUID) (*model.Budget, error) {
+	b, err := s.repo.GetBudget(ctx, id)
+	if err != nil {
+		return nil, mapRepoErr(err, ErrBudgetNotFound)
+	}
+	return b, nil
+}
+
+func (s *Usecase) UpdateBudget(ctx context.Context, b *model.Budget) error {
+	if err := validateBudget(b); err != nil {
+		return err
+	}
+	return mapBudgetErr(s.repo.UpdateBudget(ctx, b))
+}
+
+func (s *Usecase) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	return mapRepoErr(s.repo.DeleteBudget(ctx, id), ErrBudgetNotFound)
+}
+
+func (s *Usecase) ListBudgets(ctx context.Context, userID *uuid.UUID) ([]model.Budget, error) {
+	return s.repo.ListBudgets(ctx, userID)
+}
+
+// BudgetStatus возвращает расход по бюджету за месяц month (по умолчанию — текущий).
+func (s *Usecase) BudgetStatus(ctx context.Context, id uuid.UUID, month *time.Time) (*model.BudgetStatus, error) {
+	b, err := s.GetBudget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	status, err := s.budgetStatus(ctx, *b, monthOrCurrent(month))
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// BudgetStatuses возвращает расход по всем бюджетам пользователя за месяц month
+// (по умолчанию — текущий).
+func (s *Usecase) BudgetStatuses(ctx context.Context, userID uuid.UUID, month *time.Time) ([]model.BudgetStatus, error) {
+	budgets, err := s.repo.ListBudgets(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+	m := monthOrCurrent(month)
+	statuses := make([]model.BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		status, err := s.budgetStatus(ctx, b, m)
+		if err != nil {
+			return nil, fmt.Errorf("budget %s: %w", b.ID, err)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// budgetStatus считает все списания месяца по подпискам бюджета в его валюте.
+func (s *Usecase) budgetStatus(ctx context.Context, b model.Budget, month time.Time) (model.BudgetStatus, error) {
+	filter := model.SubscriptionFilter{UserID: &b.UserID}
+	if b.Category != "" {
+		filter.Category = &b.Category
+	}
+	amounts, err := s.repo.ForecastByMonth(ctx, filter, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return model.BudgetStatus{}, err
+	}
+	byCurrency := make(map[string]int, len(amounts))
+	for _, a := range amounts {
+		byCurrency[a.Currency] += a.Amount
+	}
+	total, err := s.summarize(ctx, byCurrency, b.Currency)
+	if err != nil {
+		return model.BudgetStatus{}, err
+	}
+
+	status := model.BudgetStatus{
+		Budget:    b,
+		Month:     month,
+		Spent:     *total.Total,
+		Remaining: b.Amount - *total.Total,
+		Exceeded:  *total.Total > b.Amount,
+	}
+	if b.Amount > 0 {
+		status.Percent = status.Spent * 100 / b.Amount
+	}
+	return status, nil
+}
+
+// budgetCheck состояние бюджетов, затронутых подпиской, до её сохранения.
+type budgetCheck struct {
+	month  time.Time
+	before []model.BudgetStatus
+}
+
+// prepareBudgetCheck запоминает состояние бюджетов пользователя на месяц ближайшего
+// платного списания подписки. Возвращает nil, если проверять нечего.
+// Ошибки не прерывают сохранение подписки и только пишутся в лог.
+func (s *Usecase) prepareBudgetCheck(ctx context.Context, sub *model.Subscription) *budgetCheck {
+	if s.notifier == nil {
+		return nil
+	}
+	month, ok := nextChargeMonth(sub)
+	if !ok {
+		return nil
+	}
+	budgets, err := s.repo.ListBudgets(ctx, &sub.UserID)
+	if err != nil {
+		log.Printf("budget check for user %s: %v", sub.UserID, err)
+		return nil
+	}
+
+	check := &budgetCheck{month: month}
+	for _, b := range budgets {
+		if b.Category != "" && b.Category != sub.Category {
+			continue
+		}
+		status, err := s.budgetStatus(ctx, b, month)
+		if err != nil {
+			log.Printf("budget check for user %s: budget %s: %v", sub.UserID, b.ID, err)
+			continue
+		}
+		check.before = append(check.before, status)
+	}
+	if len(check.before) == 0 {
+		return nil
+	}
+	return check
+}
+
+// notifyBudgets пересчитывает бюджеты после сохранения подписки и сообщает о тех,
+// которые она вывела за лимит. Уже превышенные бюджеты повторно не сообщаются.
+func (s *Usecase) notifyBudgets(ctx context.Context, sub *model.Subscription, check *budgetCheck) {
+	if check == nil {
+		return
+	}
+	for _, before := range check.before {
+		if before.Exceeded {
+			continue
+		}
+		after, err := s.budgetStatus(ctx, before.Budget, check.month)
+		if err != nil {
+			log.Printf("budget check for user %s: budget %s: %v", sub.UserID, before.Budget.ID, err)
+			continue
+		}
+		if after.Exceeded {
+			s.notifier.BudgetExceeded(ctx, model.BudgetAlert{Status: after, SubscriptionID: sub.ID})
+		}
+	}
+}
+
+// nextChargeMonth возвращает месяц ближайшего платного списания подписки
+// начиная с сегодняшнего дня.
+func nextChargeMonth(sub *model.Subscription) (time.Time, bool) {
+	at, ok := sub.NextPaidCharge(today())
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC), true
+}
+
+func monthOrCurrent(month *time.Time) time.Time {
+	if month != nil {
+		return *month
+	}
+	return currentMonth()
+}
+
+func mapBudgetErr(err error) error {
+	if errors.Is(err, repository.ErrConflict) {
+		return ErrBudgetExists
+	}
+	return mapRepoErr(err, ErrBudgetNotFound)
+}
+
+// validateBudget проверяет бюджет и приводит категорию и валюту к каноническому виду.
+func validateBudget(b *model.Budget) error {
+	b.Category = model.NormalizeLabel(b.Category)
+	b.Currency = strings.ToUpper(strings.TrimSpace(b.Currency))
+	if b.Currency == "" {
+		b.Currency = defaultCurrency
+	}
+
+	if b.UserID == uuid.Nil {
+		return NewValidationError("user_id", "user_id must not be empty")
+	}
+	if b.Amount <= 0 {
+		return NewValidationError("amount", "amount must be a positive integer")
+	}
+	if !exchange.ValidCode(b.Currency) {
+		return NewValidationError("currency", "currency must be an ISO 4217 code")
+	}
+	return nil
+}
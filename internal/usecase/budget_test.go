@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestValidateBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    model.Budget
+		wantField string
+	}{
+		{name: "valid", budget: model.Budget{UserID: uuid.New(), Amount: 1000, Currency: " usd "}},
+		{name: "default currency", budget: model.Budget{UserID: uuid.New(), Amount: 1000}},
+		{name: "no user", budget: model.Budget{Amount: 1000}, wantField: "user_id"},
+		{name: "zero amount", budget: model.Budget{UserID: uuid.New()}, wantField: "amount"},
+		{name: "invalid currency", budget: model.Budget{UserID: uuid.New(), Amount: 1000, Currency: "рубль"}, wantField: "currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBudget(&tt.budget)
+			if tt.wantField == "" {
+				require.NoError(t, err)
+				assert.Len(t, tt.budget.Currency, 3)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestBudgetStatus(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), rubRates{"RUB": 1, "USD": 80}, nil, nil)
+	userID := uuid.New()
+
+	b := &model.Budget{UserID: userID, Amount: 100000, Currency: "RUB"}
+	require.NoError(t, s.CreateBudget(ctx, b))
+	assert.ErrorIs(t, s.CreateBudget(ctx, &model.Budget{UserID: userID, Amount: 100}), ErrBudgetExists)
+
+	for _, sub := range []struct {
+		price    int
+		currency string
+	}{{price: 50000, currency: "RUB"}, {price: 999, currency: "USD"}} {
+		created := validSubscription()
+		created.UserID = userID
+		created.Price = sub.price
+		created.Currency = sub.currency
+		require.NoError(t, s.CreateSubscription(ctx, created, true))
+	}
+
+	// 500 ₽ и 9,99 $ по курсу 80 превышают лимит в 1000 ₽
+	status, err := s.BudgetStatus(ctx, b.ID, ptr(month(2025, 3)))
+	require.NoError(t, err)
+	assert.Equal(t, 50000+79920, status.Spent)
+	assert.Equal(t, 100000-50000-79920, status.Remaining)
+	assert.Equal(t, 129, status.Percent)
+	assert.True(t, status.Exceeded)
+
+	// До начала подписок бюджет не расходуется
+	statuses, err := s.BudgetStatuses(ctx, userID, ptr(month(2024, 12)))
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, 0, statuses[0].Spent)
+	assert.False(t, statuses[0].Exceeded)
+}
+
+func TestBudgetAlerts(t *testing.T) {
+	ctx := context.Background()
+	var alerts []model.BudgetAlert
+	notifier := BudgetNotifierFunc(func(_ context.Context, alert model.BudgetAlert) {
+		alerts = append(alerts, alert)
+	})
+	s := New(repository.NewMemoryRepository(), nil, notifier, nil)
+	userID := uuid.New()
+	require.NoError(t, s.CreateBudget(ctx, &model.Budget{UserID: userID, Amount: 2000}))
+
+	next := currentMonth().AddDate(0, 1, 0)
+	later := currentMonth().AddDate(0, 3, 0)
+
+	// Шаги выполняются по порядку и копят расходы пользователя
+	steps := []struct {
+		name      string
+		price     int
+		start     time.Time
+		end       *time.Time
+		trialEnd  *time.Time
+		wantMonth *time.Time
+	}{
+		{name: "within budget", price: 1500, start: next, trialEnd: &later},
+		// Ближайшее платное списание после пробного периода: проверяется месяц later
+		{name: "deferred by trial", price: 1000, start: later, wantMonth: &later},
+		{name: "already exceeded", price: 100, start: later},
+		{name: "other month within budget", price: 1900, start: next},
+		{name: "ended in the past", price: 5000, start: month(2024, 1), end: ptr(month(2024, 6))},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			alerts = nil
+			sub := validSubscription()
+			sub.UserID = userID
+			sub.Price = step.price
+			sub.StartDate = step.start
+			sub.EndDate = step.end
+			sub.TrialEndDate = step.trialEnd
+			require.NoError(t, s.CreateSubscription(ctx, sub, true))
+
+			if step.wantMonth == nil {
+				assert.Empty(t, alerts)
+				return
+			}
+			require.Len(t, alerts, 1)
+			assert.Equal(t, sub.ID, alerts[0].SubscriptionID)
+			assert.True(t, alerts[0].Status.Month.Equal(*step.wantMonth))
+			assert.True(t, alerts[0].Status.Exceeded)
+			assert.Equal(t, 2500, alerts[0].Status.Spent)
+		})
+	}
+}
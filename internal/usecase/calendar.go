@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/calendar"
+	"subscriptions/internal/model"
+)
+
+var (
+	ErrCalendarDisabled = newError(ErrNotFound, "calendar feed is not configured")
+	ErrCalendarToken    = newError(ErrForbidden, "invalid calendar token")
+)
+
+// RenewalCalendar проверяет токен и возвращает серии будущих списаний по подпискам пользователя.
+// Серия делится на части в месяцы запланированных изменений цены; пауза без даты
+// возобновления завершает серию, а списания внутри закрытых пауз пропускаются.
+func (s *Usecase) RenewalCalendar(ctx context.Context, userID uuid.UUID, token string) ([]model.RenewalSeries, error) {
+	if len(s.calendarSecret) == 0 {
+		return nil, ErrCalendarDisabled
+	}
+	if !calendar.Verify(s.calendarSecret, userID, token) {
+		return nil, ErrCalendarToken
+	}
+
+	var subs []model.Subscription
+	err := s.repo.ListInBatches(ctx, model.SubscriptionFilter{UserID: &userID}, exportBatchSize, func(batch []model.Subscription) error {
+		subs = append(subs, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	from := today()
+	series := []model.RenewalSeries{}
+	for i := range subs {
+		sub := &subs[i]
+		if _, ok := sub.NextPaidCharge(from); !ok {
+			continue
+		}
+		pauses, err := s.repo.ListPauses(ctx, sub.ID)
+		if err != nil {
+			return nil, err
+		}
+		prices, err := s.repo.ListPriceChanges(ctx, sub.ID)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, renewalSeries(sub, pauses, prices, from)...)
+	}
+	return series, nil
+}
+
+// renewalSeries раскладывает платные списания подписки начиная с from на серии с постоянной ценой.
+func renewalSeries(sub *model.Subscription, pauses []model.Pause, prices []model.PriceChange, from time.Time) []model.RenewalSeries {
+	// end — граница (не включительно), после которой списаний нет
+	end := sub.ActiveUntil()
+	var lastResume *time.Time
+	for _, p := range pauses {
+		switch {
+		case p.ResumedAt == nil:
+			if end == nil || p.PausedFrom.Before(*end) {
+				end = &p.PausedFrom
+			}
+		case lastResume == nil || p.ResumedAt.After(*lastResume):
+			lastResume = p.ResumedAt
+		}
+	}
+
+	// Границы частей: начало серии и даты будущих изменений цены
+	type part struct {
+		from      time.Time
+		priceFrom *time.Time
+	}
+	parts := []part{{from: from}}
+	for i := range prices {
+		at := prices[i].EffectiveFrom
+		if at.After(from) && (end == nil || at.Before(*end)) {
+			parts = append(parts, part{from: at, priceFrom: &prices[i].EffectiveFrom})
+		}
+	}
+	// Первая часть наследует идентификатор от последнего уже вступившего в силу изменения цены,
+	// чтобы серия не меняла UID, когда наступает запланированное изменение
+	for i := len(prices) - 1; i >= 0; i-- {
+		if !prices[i].EffectiveFrom.After(from) {
+			parts[0].priceFrom = &prices[i].EffectiveFrom
+			break
+		}
+	}
+
+	var out []model.RenewalSeries
+	for i, p := range parts {
+		partEnd := end
+		if i+1 < len(parts) {
+			partEnd = &parts[i+1].from
+		}
+		start, ok := sub.NextPaidCharge(p.from)
+		if !ok || (partEnd != nil && !start.Before(*partEnd)) {
+			continue
+		}
+
+		// Списания перебираются, только пока их число конечно или пока идут паузы
+		limit := partEnd
+		if limit == nil && lastResume != nil && lastResume.After(start) {
+			limit = lastResume
+		}
+		var until *time.Time
+		var except []time.Time
+		paid := true
+		if limit != nil {
+			charges := sub.ChargesBetween(start, *limit)
+			paid = false
+			for _, at := range charges {
+				if model.PausedAt(pauses, at) {
+					except = append(except, at)
+				} else {
+					paid = true
+				}
+			}
+			if partEnd != nil {
+				until = &charges[len(charges)-1]
+			}
+		}
+		if !paid {
+			continue
+		}
+
+		out = append(out, model.RenewalSeries{
+			SubscriptionID: sub.ID,
+			ServiceName:    sub.ServiceName,
+			Amount:         model.PriceAt(sub.Price, prices, start),
+			Currency:       sub.Currency,
+			Start:          start,
+			Period:         sub.BillingPeriod,
+			Interval:       sub.BillingInterval,
+			Until:          until,
+			Except:         except,
+			PriceFrom:      p.priceFrom,
+		})
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/calendar"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestRenewalSeries(t *testing.T) {
+	from := month(2025, 3)
+	type series struct {
+		start  time.Time
+		until  *time.Time
+		amount int
+		except []time.Time
+	}
+	tests := []struct {
+		name   string
+		end    *time.Time
+		pauses []model.Pause
+		prices []model.PriceChange
+		want   []series
+	}{
+		{
+			name: "open ended",
+			want: []series{{start: month(2025, 3), amount: 79900}},
+		},
+		{
+			name: "end date",
+			end:  ptr(month(2025, 6)),
+			want: []series{{start: month(2025, 3), until: ptr(month(2025, 6)), amount: 79900}},
+		},
+		{
+			name:   "open pause ends series",
+			pauses: []model.Pause{{PausedFrom: month(2025, 5)}},
+			want:   []series{{start: month(2025, 3), until: ptr(month(2025, 4)), amount: 79900}},
+		},
+		{
+			name:   "closed pause skips charges",
+			pauses: []model.Pause{{PausedFrom: month(2025, 4), ResumedAt: ptr(month(2025, 6))}},
+			want:   []series{{start: month(2025, 3), amount: 79900, except: []time.Time{month(2025, 4), month(2025, 5)}}},
+		},
+		{
+			name:   "price change splits series",
+			prices: []model.PriceChange{{EffectiveFrom: month(2025, 5), Price: 99900}},
+			want: []series{
+				{start: month(2025, 3), until: ptr(month(2025, 4)), amount: 79900},
+				{start: month(2025, 5), amount: 99900},
+			},
+		},
+		{
+			name:   "whole series paused",
+			end:    ptr(month(2025, 4)),
+			pauses: []model.Pause{{PausedFrom: month(2025, 3), ResumedAt: ptr(month(2025, 5))}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := validSubscription()
+			sub.BillingPeriod = model.BillingMonth
+			sub.BillingInterval = 1
+			sub.EndDate = tt.end
+
+			got := renewalSeries(sub, tt.pauses, tt.prices, from)
+			require.Len(t, got, len(tt.want))
+			for i, w := range tt.want {
+				assert.Equal(t, w.start, got[i].Start)
+				assert.Equal(t, w.until, got[i].Until)
+				assert.Equal(t, w.amount, got[i].Amount)
+				assert.Equal(t, w.except, got[i].Except)
+			}
+		})
+	}
+}
+
+func TestRenewalCalendar(t *testing.T) {
+	ctx := context.Background()
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	repo := repository.NewMemoryRepository()
+	s := New(repo, nil, nil, secret)
+	sub := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+
+	tests := []struct {
+		name    string
+		usecase *Usecase
+		userID  uuid.UUID
+		token   string
+		want    int
+		wantErr error
+	}{
+		{name: "valid token", usecase: s, userID: sub.UserID, token: calendar.Token(secret, sub.UserID), want: 1},
+		{name: "other user", usecase: s, userID: uuid.New(), token: calendar.Token(secret, sub.UserID), wantErr: ErrCalendarToken},
+		{name: "empty token", usecase: s, userID: sub.UserID, wantErr: ErrCalendarToken},
+		{name: "disabled", usecase: New(repo, nil, nil, nil), userID: sub.UserID, token: calendar.Token(secret, sub.UserID), wantErr: ErrCalendarDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.usecase.RenewalCalendar(ctx, tt.userID, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, got, tt.want)
+		})
+	}
+}
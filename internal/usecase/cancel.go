@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+var ErrAlreadyCanceled = newError(ErrConflict, "subscription is already canceled")
+
+// CancelSubscription отменяет подписку, сохраняя запись для истории и аналитики.
+// effective — последний действующий месяц; если nil, подписка действует до конца
+// оплаченного периода, то есть до ближайшего платного списания, но не меньше текущего месяца.
+// Ранее заданная более ранняя дата окончания сохраняется.
+func (s *Usecase) CancelSubscription(ctx context.Context, id uuid.UUID, effective *time.Time, reason model.CancelReason) (*model.Subscription, error) {
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.CanceledAt != nil {
+		return nil, ErrAlreadyCanceled
+	}
+
+	before := *sub
+
+	if reason == "" {
+		reason = model.CancelUnspecified
+	}
+	if !reason.Valid() {
+		return nil, NewValidationError("reason", "unknown cancellation reason")
+	}
+
+	var end time.Time
+	switch next, ok := sub.NextPaidCharge(today()); {
+	case effective != nil:
+		end = *effective
+	case !ok:
+		// Платных списаний больше не будет: подписка и так заканчивается в EndDate
+		end = *sub.EndDate
+	default:
+		end = time.Date(next.Year(), next.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+		if current := currentMonth(); end.Before(current) {
+			end = current
+		}
+	}
+
+	if end.Before(sub.StartDate) {
+		if effective != nil {
+			return nil, NewValidationError("effective_date", "effective_date must be on or after start_date")
+		}
+		return nil, NewValidationError("effective_date", "subscription has no paid period yet, pass a month or delete it")
+	}
+	if sub.EndDate == nil || end.Before(*sub.EndDate) {
+		sub.EndDate = &end
+	}
+
+	now := time.Now().UTC()
+	sub.CanceledAt = &now
+	sub.CancelReason = reason
+	err = s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.Cancel(ctx, sub); err != nil {
+			return mapRepoErr(err, ErrSubscriptionNotFound)
+		}
+		return tx.audit(ctx, model.AuditCancel, &before, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// CancellationStats сводит причины отмен подписок, отменённых в месяцах [from, to].
+// Незаданные границы не ограничивают период.
+func (s *Usecase) CancellationStats(ctx context.Context, filter model.SubscriptionFilter, from, to *time.Time) (*model.CancellationStats, error) {
+	if from != nil && to != nil && from.After(*to) {
+		return nil, NewValidationError("from", "from must be before or equal to to")
+	}
+	var until *time.Time
+	if to != nil {
+		next := to.AddDate(0, 1, 0)
+		until = &next
+	}
+
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountCancelReasons(ctx, filter, from, until)
+	if err != nil {
+		return nil, err
+	}
+	stats := &model.CancellationStats{Reasons: counts}
+	for _, c := range counts {
+		stats.Total += c.Count
+	}
+	return stats, nil
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestCancelSubscription(t *testing.T) {
+	current := currentMonth()
+	tests := []struct {
+		name      string
+		modify    func(sub *model.Subscription)
+		effective *time.Time
+		reason    model.CancelReason
+		wantEnd   time.Time
+		wantField string
+	}{
+		{name: "monthly until end of period", wantEnd: current},
+		{
+			name: "yearly until end of period",
+			modify: func(sub *model.Subscription) {
+				sub.BillingPeriod = model.BillingYear
+				sub.StartDate = current.AddDate(-1, 2, 0)
+			},
+			wantEnd: current.AddDate(0, 1, 0),
+		},
+		{name: "explicit month", effective: ptr(month(2025, 6)), reason: model.CancelTooExpensive, wantEnd: month(2025, 6)},
+		{
+			name:      "earlier end date kept",
+			modify:    func(sub *model.Subscription) { sub.EndDate = ptr(month(2025, 3)) },
+			effective: ptr(month(2025, 6)),
+			wantEnd:   month(2025, 3),
+		},
+		{name: "before start", effective: ptr(month(2024, 12)), wantField: "effective_date"},
+		{name: "unknown reason", reason: "bored", wantField: "reason"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := New(repository.NewMemoryRepository(), nil, nil, nil)
+			sub := validSubscription()
+			if tt.modify != nil {
+				tt.modify(sub)
+			}
+			require.NoError(t, s.CreateSubscription(ctx, sub, false))
+
+			canceled, err := s.CancelSubscription(ctx, sub.ID, tt.effective, tt.reason)
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnd, *canceled.EndDate)
+			assert.NotNil(t, canceled.CanceledAt)
+			if tt.reason == "" {
+				assert.Equal(t, model.CancelUnspecified, canceled.CancelReason)
+			}
+
+			_, err = s.CancelSubscription(ctx, sub.ID, nil, "")
+			assert.ErrorIs(t, err, ErrAlreadyCanceled)
+		})
+	}
+}
+
+func TestCancellationStats(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	for _, reason := range []model.CancelReason{model.CancelTooExpensive, model.CancelNotUsing, model.CancelTooExpensive, ""} {
+		sub := validSubscription()
+		require.NoError(t, s.CreateSubscription(ctx, sub, true))
+		_, err := s.CancelSubscription(ctx, sub.ID, nil, reason)
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.CreateSubscription(ctx, validSubscription(), true))
+
+	current := currentMonth()
+	lastMonth := current.AddDate(0, -1, 0)
+	tests := []struct {
+		name      string
+		from, to  *time.Time
+		want      *model.CancellationStats
+		wantField string
+	}{
+		{
+			name: "all time",
+			want: &model.CancellationStats{Total: 4, Reasons: []model.ReasonCount{
+				{Reason: model.CancelTooExpensive, Count: 2},
+				{Reason: model.CancelNotUsing, Count: 1},
+				{Reason: model.CancelUnspecified, Count: 1},
+			}},
+		},
+		{name: "current month", from: &current, to: &current, want: &model.CancellationStats{Total: 4, Reasons: []model.ReasonCount{
+			{Reason: model.CancelTooExpensive, Count: 2},
+			{Reason: model.CancelNotUsing, Count: 1},
+			{Reason: model.CancelUnspecified, Count: 1},
+		}}},
+		{name: "before cancellations", to: &lastMonth, want: &model.CancellationStats{Reasons: []model.ReasonCount{}}},
+		{name: "from after to", from: &current, to: &lastMonth, wantField: "from"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := s.CancellationStats(ctx, model.SubscriptionFilter{}, tt.from, tt.to)
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, stats)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+var (
+	ErrServiceNotFound = fmt.Errorf("service %w", ErrNotFound)
+	ErrAliasTaken      = newError(ErrConflict, "service name or alias is already used by another service")
+)
+
+func (s *Usecase) CreateService(ctx context.Context, svc *model.Service) error {
+	if err := validateService(svc); err != nil {
+		return err
+	}
+	return mapServiceErr(s.repo.CreateService(ctx, svc))
+}
+
+func (s *Usecase) GetService(ctx context.Context, id uuid.UUID) (*model.Service, error) {
+	svc, err := s.repo.GetService(ctx, id)
+	if err != nil {
+		return nil, mapRepoErr(err, ErrServiceNotFound)
+	}
+	return svc, nil
+}
+
+func (s *Usecase) UpdateService(ctx context.Context, svc *model.Service) error {
+	if err := validateService(svc); err != nil {
+		return err
+	}
+	return mapServiceErr(s.repo.UpdateService(ctx, svc))
+}
+
+// DeleteService удаляет запись каталога. Связанные подписки сохраняются без ссылки на каталог.
+func (s *Usecase) DeleteService(ctx context.Context, id uuid.UUID) error {
+	return mapRepoErr(s.repo.DeleteService(ctx, id), ErrServiceNotFound)
+}
+
+func (s *Usecase) ListServices(ctx context.Context) ([]model.Service, error) {
+	return s.repo.ListServices(ctx)
+}
+
+// MatchService ищет запись каталога по названию сервиса без учёта регистра и пробелов.
+// Возвращает nil, если совпадения нет.
+func (s *Usecase) MatchService(ctx context.Context, name string) (*model.Service, error) {
+	alias := model.NormalizeLabel(name)
+	if alias == "" {
+		return nil, nil
+	}
+	svc, err := s.repo.FindServiceByAlias(ctx, alias)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return svc, err
+}
+
+// resolveFilter приводит фильтр по названию сервиса к каталогу: название, совпавшее
+// с псевдонимом, заменяется фильтром по записи каталога, так что "Netflix", "netflix "
+// и "NETFLIX" выбирают одни и те же подписки. Без совпадения название сравнивается
+// без учёта регистра и пробелов.
+func (s *Usecase) resolveFilter(ctx context.Context, filter model.SubscriptionFilter) (model.SubscriptionFilter, error) {
+	if filter.ServiceName == nil {
+		return filter, nil
+	}
+	name := model.NormalizeLabel(*filter.ServiceName)
+	filter.ServiceName = &name
+	svc, err := s.MatchService(ctx, name)
+	if err != nil {
+		return filter, err
+	}
+	switch {
+	case svc == nil:
+	case filter.ServiceID == nil:
+		filter.ServiceID, filter.ServiceName = &svc.ID, nil
+	case *filter.ServiceID == svc.ID:
+		filter.ServiceName = nil
+	}
+	return filter, nil
+}
+
+// resolveService связывает подписку с каталогом: по явному ServiceID или по псевдониму
+// названия. У связанной подписки название заменяется каноническим, а пустая категория —
+// категорией сервиса по умолчанию.
+func (s *Usecase) resolveService(ctx context.Context, sub *model.Subscription) error {
+	var svc *model.Service
+	if sub.ServiceID != nil {
+		found, err := s.repo.GetService(ctx, *sub.ServiceID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return NewValidationError("service_id", "service not found in catalog")
+		}
+		if err != nil {
+			return err
+		}
+		svc = found
+	} else {
+		found, err := s.MatchService(ctx, sub.ServiceName)
+		if err != nil {
+			return err
+		}
+		if found == nil {
+			return nil
+		}
+		svc = found
+	}
+
+	sub.ServiceID = &svc.ID
+	sub.ServiceName = svc.Name
+	if sub.Category == "" {
+		sub.Category = svc.DefaultCategory
+	}
+	return nil
+}
+
+func mapServiceErr(err error) error {
+	if errors.Is(err, repository.ErrConflict) {
+		return ErrAliasTaken
+	}
+	return mapRepoErr(err, ErrServiceNotFound)
+}
+
+// validateService проверяет запись каталога и нормализует псевдонимы:
+// название сервиса всегда входит в их число.
+func validateService(svc *model.Service) error {
+	svc.Name = strings.TrimSpace(svc.Name)
+	svc.DefaultCategory = model.NormalizeLabel(svc.DefaultCategory)
+	svc.Aliases = normalizeLabels(append([]string{svc.Name}, svc.Aliases...))
+
+	if svc.Name == "" {
+		return NewValidationError("name", "name must not be empty")
+	}
+	if !validURL(svc.URL) {
+		return NewValidationError("url", "url must be an absolute http(s) URL")
+	}
+	if !validURL(svc.LogoURL) {
+		return NewValidationError("logo_url", "logo_url must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+// validURL допускает пустую строку или абсолютный http(s) URL.
+func validURL(raw string) bool {
+	if raw == "" {
+		return true
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
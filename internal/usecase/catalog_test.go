@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestValidateService(t *testing.T) {
+	tests := []struct {
+		name      string
+		svc       model.Service
+		aliases   []string
+		wantField string
+	}{
+		{name: "valid", svc: model.Service{Name: " Netflix ", Aliases: []string{"NETFLIX.com", "netflix"}, URL: "https://netflix.com"}, aliases: []string{"netflix", "netflix.com"}},
+		{name: "empty name", svc: model.Service{Name: "  "}, wantField: "name"},
+		{name: "relative url", svc: model.Service{Name: "Netflix", URL: "netflix.com"}, wantField: "url"},
+		{name: "ftp logo", svc: model.Service{Name: "Netflix", LogoURL: "ftp://netflix.com/logo.png"}, wantField: "logo_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateService(&tt.svc)
+			if tt.wantField == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "Netflix", tt.svc.Name)
+				assert.Equal(t, tt.aliases, tt.svc.Aliases)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestServiceMatching(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	netflix := &model.Service{Name: "Netflix", Aliases: []string{"нетфликс"}, DefaultCategory: "Entertainment"}
+	require.NoError(t, s.CreateService(ctx, netflix))
+	assert.ErrorIs(t, s.CreateService(ctx, &model.Service{Name: " NETFLIX"}), ErrAliasTaken)
+
+	tests := []struct {
+		name    string
+		service string
+		linked  bool
+	}{
+		{name: "canonical", service: "Netflix", linked: true},
+		{name: "case and spaces", service: "  NETFLIX ", linked: true},
+		{name: "alias", service: "Нетфликс", linked: true},
+		{name: "unknown", service: "Spotify"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := validSubscription()
+			sub.ServiceName = tt.service
+			require.NoError(t, s.CreateSubscription(ctx, sub, true))
+			if !tt.linked {
+				assert.Nil(t, sub.ServiceID)
+				assert.Equal(t, tt.service, sub.ServiceName)
+				return
+			}
+			require.NotNil(t, sub.ServiceID)
+			assert.Equal(t, netflix.ID, *sub.ServiceID)
+			assert.Equal(t, "Netflix", sub.ServiceName)
+			assert.Equal(t, "entertainment", sub.Category)
+		})
+	}
+}
+
+func TestResolveFilter(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	s := New(repo, nil, nil, nil)
+
+	// Подписки, сохранённые до появления записи в каталоге, не связаны с ним
+	for _, name := range []string{"нетфликс", "Spotify "} {
+		sub := validSubscription()
+		sub.ServiceName = name
+		require.NoError(t, repo.Create(ctx, sub))
+	}
+	netflix := &model.Service{Name: "Netflix", Aliases: []string{"нетфликс"}}
+	require.NoError(t, s.CreateService(ctx, netflix))
+	sub := validSubscription()
+	sub.ServiceName = "Netflix"
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+
+	tests := []struct {
+		name   string
+		filter model.SubscriptionFilter
+		want   []string
+	}{
+		{name: "catalog name", filter: model.SubscriptionFilter{ServiceName: ptr("NETFLIX ")}, want: []string{"Netflix", "нетфликс"}},
+		{name: "alias", filter: model.SubscriptionFilter{ServiceName: ptr("Нетфликс")}, want: []string{"Netflix", "нетфликс"}},
+		{name: "service id", filter: model.SubscriptionFilter{ServiceID: &netflix.ID}, want: []string{"Netflix", "нетфликс"}},
+		{name: "not in catalog", filter: model.SubscriptionFilter{ServiceName: ptr("spotify")}, want: []string{"Spotify "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := s.ListSubscriptions(ctx, tt.filter, -1, 0)
+			require.NoError(t, err)
+			names := []string{}
+			for _, sub := range list.Items {
+				names = append(names, sub.ServiceName)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestNormalizeLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "none", tags: nil, want: nil},
+		{name: "case and spaces", tags: []string{"  Work   Tools ", "family"}, want: []string{"family", "work tools"}},
+		{name: "duplicates and blanks", tags: []string{"Family", "family", " ", ""}, want: []string{"family"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeLabels(tt.tags))
+		})
+	}
+}
+
+func TestCalculateTotalByCategory(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), rubRates{"RUB": 1, "USD": 80}, nil, nil)
+
+	for _, sub := range []struct {
+		category string
+		price    int
+		currency string
+	}{
+		{category: " Entertainment", price: 1000, currency: "RUB"},
+		{category: "entertainment", price: 100, currency: "USD"},
+		{category: "Work  tools", price: 2000, currency: "RUB"},
+	} {
+		created := validSubscription()
+		created.Category = sub.category
+		created.Price = sub.price
+		created.Currency = sub.currency
+		created.EndDate = ptr(month(2025, 1))
+		require.NoError(t, s.CreateSubscription(ctx, created, true))
+	}
+
+	totals, err := s.CalculateTotalByCategory(ctx, model.SubscriptionFilter{}, month(2025, 1), month(2025, 1), "RUB")
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+	assert.Equal(t, "entertainment", totals[0].Category)
+	assert.Equal(t, 1000+8000, *totals[0].Total.Total)
+	assert.Equal(t, "work tools", totals[1].Category)
+	assert.Equal(t, 2000, *totals[1].Total.Total)
+
+	// Без валюты итог категории с двумя валютами не складывается
+	totals, err = s.CalculateTotalByCategory(ctx, model.SubscriptionFilter{}, month(2025, 1), month(2025, 1), "")
+	require.NoError(t, err)
+	assert.Nil(t, totals[0].Total.Total)
+	assert.Equal(t, map[string]int{"RUB": 1000, "USD": 100}, totals[0].ByCurrency)
+}
+
+func TestCategoryBudgets(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	userID := uuid.New()
+
+	overall := &model.Budget{UserID: userID, Amount: 100000}
+	entertainment := &model.Budget{UserID: userID, Category: " Entertainment ", Amount: 1500}
+	require.NoError(t, s.CreateBudget(ctx, overall))
+	require.NoError(t, s.CreateBudget(ctx, entertainment))
+	assert.Equal(t, "entertainment", entertainment.Category)
+	assert.ErrorIs(t, s.CreateBudget(ctx, &model.Budget{UserID: userID, Category: "entertainment", Amount: 100}), ErrBudgetExists)
+
+	for _, category := range []string{"entertainment", "work tools"} {
+		sub := validSubscription()
+		sub.UserID = userID
+		sub.Category = category
+		sub.Price = 1000
+		require.NoError(t, s.CreateSubscription(ctx, sub, true))
+	}
+
+	statuses, err := s.BudgetStatuses(ctx, userID, ptr(month(2025, 2)))
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "", statuses[0].Budget.Category)
+	assert.Equal(t, 2000, statuses[0].Spent)
+	assert.Equal(t, "entertainment", statuses[1].Budget.Category)
+	assert.Equal(t, 1000, statuses[1].Spent)
+	assert.Equal(t, 66, statuses[1].Percent)
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestUpcomingCharges(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	day := today()
+
+	// Подписка списывается каждую неделю начиная с сегодняшнего дня
+	sub := validSubscription()
+	sub.StartDate = day
+	sub.BillingPeriod = model.BillingWeek
+	sub.BillingInterval = 1
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+
+	tests := []struct {
+		name      string
+		days      int
+		want      int
+		wantField string
+	}{
+		{name: "today", days: 1, want: 1},
+		{name: "two weeks", days: 14, want: 2},
+		{name: "two weeks and a day", days: 15, want: 3},
+		{name: "zero days", days: 0, wantField: "days"},
+		{name: "over a year", days: maxLookaheadDays + 1, wantField: "days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charges, err := s.UpcomingCharges(ctx, model.SubscriptionFilter{}, tt.days)
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, charges, tt.want)
+			assert.True(t, charges[0].Date.Equal(day))
+			assert.Equal(t, sub.Price, charges[0].Amount)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	sub := validSubscription()
+	sub.EndDate = ptr(month(2025, 3))
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	require.NoError(t, s.DeleteSubscription(ctx, sub.ID))
+
+	list, err := s.ListSubscriptions(ctx, model.SubscriptionFilter{}, -1, 0)
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+
+	// Прошлые списания удалённой подписки остаются в суммах
+	total, err := s.CalculateTotal(ctx, model.SubscriptionFilter{}, month(2025, 1), month(2025, 12), "")
+	require.NoError(t, err)
+	assert.Equal(t, 3*sub.Price, *total.Total)
+}
+
+func TestRestoreSubscription(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	deleted := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, deleted, false))
+	require.NoError(t, s.DeleteSubscription(ctx, deleted.ID))
+	live := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, live, false))
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		wantErr error
+	}{
+		{name: "deleted", id: deleted.ID},
+		{name: "not deleted", id: live.ID, wantErr: ErrNotDeleted},
+		{name: "missing", id: uuid.New(), wantErr: ErrSubscriptionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored, err := s.RestoreSubscription(ctx, tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.False(t, restored.DeletedAt.Valid)
+		})
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	sub := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	require.NoError(t, s.DeleteSubscription(ctx, sub.ID))
+
+	purged, err := s.PurgeDeleted(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = s.PurgeDeleted(ctx, -time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	_, err = s.RestoreSubscription(ctx, sub.ID)
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+}
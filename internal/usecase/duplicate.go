@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+const (
+	// duplicateNameSimilarity доля совпадающих символов, начиная с которой названия считаются почти одинаковыми
+	duplicateNameSimilarity = 0.8
+	// duplicatePriceTolerance допустимое отличие цен почти одинаковых подписок
+	duplicatePriceTolerance = 0.1
+)
+
+// DuplicateError создаваемая подписка похожа на уже существующую подписку пользователя.
+type DuplicateError struct {
+	Existing *model.Subscription
+	Reason   model.DuplicateReason
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("subscription looks like a duplicate of %s (%s)", e.Existing.ID, e.Existing.ServiceName)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrConflict
+}
+
+// FindDuplicates ищет пары подписок пользователя, которые действуют одновременно и либо
+// относятся к одному сервису, либо почти совпадают по названию и цене. Пары упорядочены
+// по началу общего периода.
+func (s *Usecase) FindDuplicates(ctx context.Context, userID uuid.UUID) ([]model.Duplicate, error) {
+	subs, err := s.userSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := []model.Duplicate{}
+	for i := range subs {
+		for j := i + 1; j < len(subs); j++ {
+			reason, ok := duplicateReason(&subs[i], &subs[j])
+			if !ok {
+				continue
+			}
+			from, until, _ := overlap(&subs[i], &subs[j])
+			dup := model.Duplicate{
+				Reason:        reason,
+				Subscriptions: []model.Subscription{subs[i], subs[j]},
+				OverlapFrom:   from,
+			}
+			if until != nil {
+				last := until.AddDate(0, -1, 0)
+				dup.OverlapTo = &last
+			}
+			duplicates = append(duplicates, dup)
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].OverlapFrom.Before(duplicates[j].OverlapFrom)
+	})
+	return duplicates, nil
+}
+
+// checkDuplicate возвращает *DuplicateError, если у владельца sub уже есть похожая подписка.
+func (s *Usecase) checkDuplicate(ctx context.Context, sub *model.Subscription) error {
+	subs, err := s.userSubscriptions(ctx, sub.UserID)
+	if err != nil {
+		return err
+	}
+	for i := range subs {
+		if reason, ok := duplicateReason(sub, &subs[i]); ok {
+			return &DuplicateError{Existing: &subs[i], Reason: reason}
+		}
+	}
+	return nil
+}
+
+// userSubscriptions возвращает все неудалённые подписки пользователя.
+func (s *Usecase) userSubscriptions(ctx context.Context, userID uuid.UUID) ([]model.Subscription, error) {
+	var subs []model.Subscription
+	err := s.repo.ListInBatches(ctx, model.SubscriptionFilter{UserID: &userID}, exportBatchSize, func(batch []model.Subscription) error {
+		subs = append(subs, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// duplicateReason сообщает, похожи ли подписки на дубликаты: они должны действовать
+// одновременно и относиться к одному сервису или почти совпадать по названию и цене.
+func duplicateReason(a, b *model.Subscription) (model.DuplicateReason, bool) {
+	if a.ID == b.ID && a.ID != uuid.Nil {
+		return "", false
+	}
+	if _, _, ok := overlap(a, b); !ok {
+		return "", false
+	}
+	if (a.ServiceID != nil && b.ServiceID != nil && *a.ServiceID == *b.ServiceID) ||
+		model.NormalizeLabel(a.ServiceName) == model.NormalizeLabel(b.ServiceName) {
+		return model.DuplicateSameService, true
+	}
+	if a.Currency == b.Currency && a.BillingPeriod == b.BillingPeriod && a.BillingInterval == b.BillingInterval &&
+		similarPrices(a.Price, b.Price) && similarNames(a.ServiceName, b.ServiceName) {
+		return model.DuplicateSimilar, true
+	}
+	return "", false
+}
+
+// overlap возвращает общий период действия подписок [from, until); until nil — без конца.
+func overlap(a, b *model.Subscription) (from time.Time, until *time.Time, ok bool) {
+	from = a.StartDate
+	if b.StartDate.After(from) {
+		from = b.StartDate
+	}
+	until = a.ActiveUntil()
+	if end := b.ActiveUntil(); end != nil && (until == nil || end.Before(*until)) {
+		until = end
+	}
+	return from, until, until == nil || from.Before(*until)
+}
+
+func similarPrices(a, b int) bool {
+	diff, largest := a-b, max(a, b)
+	if diff < 0 {
+		diff = -diff
+	}
+	return float64(diff) <= float64(largest)*duplicatePriceTolerance
+}
+
+// similarNames сравнивает названия без учёта регистра, пробелов и знаков
+// по расстоянию Левенштейна: "YouTube Premium" и "Youtube-Premium" совпадают,
+// "Spotify Family" и "Spotify Famliy" почти совпадают.
+func similarNames(a, b string) bool {
+	ra, rb := compactName(a), compactName(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return false
+	}
+	return 1-float64(editDistance(ra, rb))/float64(longest) >= duplicateNameSimilarity
+}
+
+func compactName(name string) []rune {
+	var runes []rune
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
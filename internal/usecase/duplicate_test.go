@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestSimilarNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "YouTube Premium", b: "Youtube-Premium", want: true},
+		{a: "Spotify Family", b: "Spotify Famliy", want: true},
+		{a: "Яндекс Плюс", b: "яндекс.плюс", want: true},
+		{a: "Spotify", b: "Netflix", want: false},
+		{a: "Spotify Family", b: "Spotify Duo", want: false},
+		{a: "Okko", b: "Okko Sport", want: false},
+		{a: "", b: "", want: false},
+		{a: "!!!", b: "???", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, similarNames(tt.a, tt.b))
+			assert.Equal(t, tt.want, similarNames(tt.b, tt.a))
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "abc", want: 3},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "famliy", b: "family", want: 2},
+		{a: "плюс", b: "плюс", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, editDistance([]rune(tt.a), []rune(tt.b)))
+		})
+	}
+}
+
+func TestSimilarPrices(t *testing.T) {
+	assert.True(t, similarPrices(29900, 29900))
+	assert.True(t, similarPrices(30000, 27000))
+	assert.True(t, similarPrices(27000, 30000))
+	assert.False(t, similarPrices(30000, 26900))
+}
+
+func TestOverlap(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      model.Subscription
+		wantFrom  time.Time
+		wantUntil *time.Time
+		wantOK    bool
+	}{
+		{
+			name:     "both open-ended",
+			a:        model.Subscription{StartDate: month(2025, 1)},
+			b:        model.Subscription{StartDate: month(2025, 3)},
+			wantFrom: month(2025, 3),
+			wantOK:   true,
+		},
+		{
+			name:      "one ends",
+			a:         model.Subscription{StartDate: month(2025, 1), EndDate: ptr(month(2025, 6))},
+			b:         model.Subscription{StartDate: month(2025, 3)},
+			wantFrom:  month(2025, 3),
+			wantUntil: ptr(month(2025, 7)),
+			wantOK:    true,
+		},
+		{
+			name:      "earlier end wins",
+			a:         model.Subscription{StartDate: month(2025, 1), EndDate: ptr(month(2025, 9))},
+			b:         model.Subscription{StartDate: month(2025, 2), EndDate: ptr(month(2025, 4))},
+			wantFrom:  month(2025, 2),
+			wantUntil: ptr(month(2025, 5)),
+			wantOK:    true,
+		},
+		{
+			// Подписка действует до конца месяца EndDate, поэтому общий месяц есть
+			name:      "end month meets start month",
+			a:         model.Subscription{StartDate: month(2025, 1), EndDate: ptr(month(2025, 3))},
+			b:         model.Subscription{StartDate: month(2025, 3)},
+			wantFrom:  month(2025, 3),
+			wantUntil: ptr(month(2025, 4)),
+			wantOK:    true,
+		},
+		{
+			name:      "one after another",
+			a:         model.Subscription{StartDate: month(2025, 1), EndDate: ptr(month(2025, 3))},
+			b:         model.Subscription{StartDate: month(2025, 4)},
+			wantFrom:  month(2025, 4),
+			wantUntil: ptr(month(2025, 4)),
+			wantOK:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, until, ok := overlap(&tt.a, &tt.b)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantFrom, from)
+			assert.Equal(t, tt.wantUntil, until)
+		})
+	}
+}
+
+func TestDuplicateReason(t *testing.T) {
+	serviceID := uuid.New()
+	base := model.Subscription{
+		ID: uuid.New(), ServiceName: "Spotify Family", Price: 29900, Currency: "RUB",
+		StartDate: month(2025, 1), BillingPeriod: model.BillingMonth, BillingInterval: 1,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(sub *model.Subscription)
+		want   model.DuplicateReason
+		wantOK bool
+	}{
+		{
+			name:   "same name in other case",
+			modify: func(sub *model.Subscription) { sub.ServiceName = " spotify  family"; sub.Price = 99900 },
+			want:   model.DuplicateSameService,
+			wantOK: true,
+		},
+		{
+			name: "same catalog entry",
+			modify: func(sub *model.Subscription) {
+				sub.ServiceName = "Spotify"
+				sub.ServiceID = &serviceID
+			},
+			want:   model.DuplicateSameService,
+			wantOK: true,
+		},
+		{
+			name:   "similar name and price",
+			modify: func(sub *model.Subscription) { sub.ServiceName = "Spotify Famliy"; sub.Price = 31900 },
+			want:   model.DuplicateSimilar,
+			wantOK: true,
+		},
+		{
+			name:   "similar name, other price",
+			modify: func(sub *model.Subscription) { sub.ServiceName = "Spotify Famliy"; sub.Price = 49900 },
+		},
+		{
+			name: "similar name, other cycle",
+			modify: func(sub *model.Subscription) {
+				sub.ServiceName = "Spotify Famliy"
+				sub.BillingPeriod = model.BillingYear
+			},
+		},
+		{
+			name:   "similar name, other currency",
+			modify: func(sub *model.Subscription) { sub.ServiceName = "Spotify Famliy"; sub.Currency = "USD" },
+		},
+		{
+			name: "same service, no common period",
+			modify: func(sub *model.Subscription) {
+				sub.StartDate = month(2024, 1)
+				sub.EndDate = ptr(month(2024, 12))
+			},
+		},
+		{
+			name:   "same subscription",
+			modify: func(sub *model.Subscription) { sub.ID = base.ID },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := base
+			a.ServiceID = &serviceID
+			b := base
+			b.ID = uuid.New()
+			tt.modify(&b)
+
+			reason, ok := duplicateReason(&a, &b)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, reason)
+		})
+	}
+}
+
+func TestCreateSubscriptionDuplicate(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	userID := uuid.New()
+	newSub := func(name string) *model.Subscription {
+		return &model.Subscription{
+			ServiceName: name, Price: 29900, Currency: "RUB", UserID: userID, StartDate: month(2025, 1),
+			BillingPeriod: model.BillingMonth, BillingInterval: 1,
+		}
+	}
+
+	first := newSub("Spotify Family")
+	require.NoError(t, s.CreateSubscription(ctx, first, false))
+
+	err := s.CreateSubscription(ctx, newSub("spotify family"), false)
+	var dup *DuplicateError
+	require.True(t, errors.As(err, &dup))
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, first.ID, dup.Existing.ID)
+	assert.Equal(t, model.DuplicateSameService, dup.Reason)
+
+	require.NoError(t, s.CreateSubscription(ctx, newSub("Spotify Famliy"), true))
+
+	duplicates, err := s.FindDuplicates(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, model.DuplicateSimilar, duplicates[0].Reason)
+	assert.Equal(t, month(2025, 1), duplicates[0].OverlapFrom)
+	assert.Nil(t, duplicates[0].OverlapTo)
+}
+
+func TestCreateSubscriptionDuplicateConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+
+	// Проверка и создание идут в одной транзакции, поэтому из одновременных запросов проходит один
+	const requests = 8
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.CreateSubscription(ctx, validSubscription(), false)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrConflict)
+	}
+	assert.Equal(t, 1, created)
+}
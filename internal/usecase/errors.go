@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"subscriptions/internal/repository"
+)
+
+// Категории доменных ошибок. Конкретные ошибки оборачивают одну из них,
+// поэтому вызывающий проверяет категорию через errors.Is.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	ErrForbidden  = errors.New("forbidden")
+	// ErrPreconditionFailed условие запроса, например ожидаемая версия записи, не выполнено
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+var (
+	ErrSubscriptionNotFound = fmt.Errorf("subscription %w", ErrNotFound)
+	ErrNotDeleted           = newError(ErrConflict, "subscription is not deleted")
+	ErrVersionMismatch      = newError(ErrPreconditionFailed, "subscription has been modified, version mismatch")
+)
+
+// kindError ошибка с собственным текстом, относящаяся к одной из категорий.
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+func newError(kind error, msg string) error {
+	return &kindError{kind: kind, msg: msg}
+}
+
+// ValidationError описывает некорректное значение конкретного поля.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+func NewValidationError(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
+// mapRepoErr переводит ошибки хранилища в доменные. notFound — ошибка,
+// которую нужно вернуть для отсутствующей записи конкретной сущности.
+func mapRepoErr(err error, notFound error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrNotFound):
+		return notFound
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return ErrVersionMismatch
+	case errors.Is(err, repository.ErrConflict):
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	default:
+		return err
+	}
+}
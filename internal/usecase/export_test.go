@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestExportSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	netflix := &model.Service{Name: "Netflix", Aliases: []string{"нетфликс"}}
+	require.NoError(t, s.CreateService(ctx, netflix))
+	for _, name := range []string{"Netflix", "Spotify"} {
+		sub := validSubscription()
+		sub.ServiceName = name
+		require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	}
+	deleted := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, deleted, true))
+	require.NoError(t, s.DeleteSubscription(ctx, deleted.ID))
+
+	tests := []struct {
+		name   string
+		filter model.SubscriptionFilter
+		want   int
+	}{
+		{name: "all", want: 2},
+		{name: "with deleted", filter: model.SubscriptionFilter{IncludeDeleted: true}, want: 3},
+		{name: "catalog alias", filter: model.SubscriptionFilter{ServiceName: ptr("Нетфликс")}, want: 1},
+		{name: "service id", filter: model.SubscriptionFilter{ServiceID: &netflix.ID, IncludeDeleted: true}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			err := s.ExportSubscriptions(ctx, tt.filter, func(batch []model.Subscription) error {
+				count += len(batch)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+		})
+	}
+}
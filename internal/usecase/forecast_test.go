@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestForecast(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	start := currentMonth()
+
+	// Бессрочная подписка дорожает через два месяца, вторая заканчивается в следующем
+	sub := validSubscription()
+	sub.Price = 1000
+	sub.StartDate = month(2025, 1)
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	require.NoError(t, s.SchedulePriceChange(ctx, &model.PriceChange{SubscriptionID: sub.ID, EffectiveFrom: start.AddDate(0, 2, 0), Price: 1500}))
+
+	ending := validSubscription()
+	ending.ServiceName = "Spotify"
+	ending.Price = 300
+	ending.StartDate = month(2025, 1)
+	ending.EndDate = ptr(start.AddDate(0, 1, 0))
+	require.NoError(t, s.CreateSubscription(ctx, ending, false))
+
+	forecast, err := s.Forecast(ctx, model.SubscriptionFilter{}, 4, "")
+	require.NoError(t, err)
+	require.Len(t, forecast, 4)
+
+	// Списание первого числа текущего месяца уже прошло, если сегодня не первое
+	first := 0
+	if today().Equal(start) {
+		first = 1000 + 300
+	}
+	for i, want := range []int{first, 1000 + 300, 1500, 1500} {
+		assert.True(t, forecast[i].Month.Equal(start.AddDate(0, i, 0)))
+		require.NotNil(t, forecast[i].Total.Total, "month %d", i)
+		assert.Equal(t, want, *forecast[i].Total.Total, "month %d", i)
+	}
+
+	for _, months := range []int{0, maxForecastMonths + 1} {
+		_, err := s.Forecast(ctx, model.SubscriptionFilter{}, months, "")
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr), "got %v", err)
+		assert.Equal(t, "months", validationErr.Field)
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"subscriptions/internal/model"
+)
+
+// maxImportRows ограничивает число строк в одном импорте
+const maxImportRows = 10000
+
+// ImportRow строка импорта: разобранная подписка или ошибка разбора.
+type ImportRow struct {
+	// Line номер строки в файле
+	Line         int
+	Subscription *model.Subscription
+	Err          error
+}
+
+// ImportSubscriptions проверяет строки по тем же правилам, что и создание подписки,
+// и, если ошибок нет и это не пробный запуск, сохраняет их в одной транзакции пакетами.
+// Ошибки строк возвращаются в результате; бюджеты при импорте не проверяются.
+func (s *Usecase) ImportSubscriptions(ctx context.Context, rows []ImportRow, dryRun bool) (*model.ImportResult, error) {
+	if len(rows) == 0 {
+		return nil, NewValidationError("file", "file has no data rows")
+	}
+	if len(rows) > maxImportRows {
+		return nil, NewValidationError("file", fmt.Sprintf("at most %d rows are allowed", maxImportRows))
+	}
+
+	res := &model.ImportResult{DryRun: dryRun, Rows: len(rows), Errors: []model.ImportRowError{}}
+	subs := make([]*model.Subscription, 0, len(rows))
+	for _, row := range rows {
+		err := row.Err
+		if err == nil {
+			err = validateSubscription(row.Subscription)
+		}
+		if err == nil {
+			err = s.resolveService(ctx, row.Subscription)
+		}
+		if err != nil {
+			if !errors.Is(err, ErrValidation) {
+				return nil, err
+			}
+			rowErr := model.ImportRowError{Row: row.Line, Message: err.Error()}
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				rowErr.Field = validationErr.Field
+			}
+			res.Errors = append(res.Errors, rowErr)
+			continue
+		}
+		subs = append(subs, row.Subscription)
+	}
+	res.Valid = len(subs)
+	if dryRun || len(res.Errors) > 0 {
+		return res, nil
+	}
+
+	err := s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.CreateBatch(ctx, subs); err != nil {
+			return mapRepoErr(err, ErrSubscriptionNotFound)
+		}
+		for _, sub := range subs {
+			if err := tx.audit(ctx, model.AuditCreate, nil, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Imported = len(subs)
+	return res, nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestImportSubscriptions(t *testing.T) {
+	invalid := func() *model.Subscription {
+		sub := validSubscription()
+		sub.Price = 0
+		return sub
+	}
+
+	tests := []struct {
+		name         string
+		rows         func() []ImportRow
+		dryRun       bool
+		want         model.ImportResult
+		wantSaved    int
+		wantErrField string
+	}{
+		{
+			name: "import",
+			rows: func() []ImportRow {
+				return []ImportRow{{Line: 2, Subscription: validSubscription()}, {Line: 3, Subscription: validSubscription()}}
+			},
+			want:      model.ImportResult{Rows: 2, Valid: 2, Imported: 2},
+			wantSaved: 2,
+		},
+		{
+			name:   "dry run",
+			rows:   func() []ImportRow { return []ImportRow{{Line: 2, Subscription: validSubscription()}} },
+			dryRun: true,
+			want:   model.ImportResult{DryRun: true, Rows: 1, Valid: 1},
+		},
+		{
+			name: "row error saves nothing",
+			rows: func() []ImportRow {
+				return []ImportRow{
+					{Line: 2, Subscription: validSubscription()},
+					{Line: 3, Subscription: invalid()},
+					{Line: 4, Err: NewValidationError("start_date", "start_date must be in MM-YYYY format")},
+				}
+			},
+			want: model.ImportResult{Rows: 3, Valid: 1, Errors: []model.ImportRowError{
+				{Row: 3, Field: "price", Message: "price must be a positive integer"},
+				{Row: 4, Field: "start_date", Message: "start_date must be in MM-YYYY format"},
+			}},
+		},
+		{name: "no rows", rows: func() []ImportRow { return nil }, wantErrField: "file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := New(repository.NewMemoryRepository(), nil, nil, nil)
+
+			res, err := s.ImportSubscriptions(ctx, tt.rows(), tt.dryRun)
+			if tt.wantErrField != "" {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantErrField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want.Errors == nil {
+				tt.want.Errors = []model.ImportRowError{}
+			}
+			assert.Equal(t, &tt.want, res)
+
+			list, err := s.ListSubscriptions(ctx, model.SubscriptionFilter{}, -1, 0)
+			require.NoError(t, err)
+			assert.Len(t, list.Items, tt.wantSaved)
+			audit, err := s.ListAuditRecords(ctx, model.AuditFilter{Operation: ptr(model.AuditCreate)}, -1, 0)
+			require.NoError(t, err)
+			assert.Len(t, audit.Items, tt.wantSaved)
+		})
+	}
+}
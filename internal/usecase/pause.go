@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+var (
+	ErrAlreadyPaused = newError(ErrConflict, "subscription is already paused")
+	ErrNotPaused     = newError(ErrConflict, "subscription is not paused")
+)
+
+// PauseSubscription приостанавливает подписку с месяца from (по умолчанию — текущего).
+// Списания в приостановленные месяцы не учитываются в суммах.
+func (s *Usecase) PauseSubscription(ctx context.Context, subscriptionID uuid.UUID, from *time.Time) (*model.Pause, error) {
+	sub, err := s.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	pauses, err := s.repo.ListPauses(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pauses {
+		if p.ResumedAt == nil {
+			return nil, ErrAlreadyPaused
+		}
+	}
+
+	pausedFrom := currentMonth()
+	if from != nil {
+		pausedFrom = *from
+	}
+
+	if pausedFrom.Before(sub.StartDate) {
+		return nil, NewValidationError("from", "pause must start on or after start_date")
+	}
+	if until := sub.ActiveUntil(); until != nil && !pausedFrom.Before(*until) {
+		return nil, NewValidationError("from", "pause must start on or before end_date")
+	}
+	for _, p := range pauses {
+		if pausedFrom.Before(*p.ResumedAt) {
+			return nil, NewValidationError("from", "pause overlaps a previous pause")
+		}
+	}
+
+	pause := &model.Pause{SubscriptionID: subscriptionID, PausedFrom: pausedFrom}
+	err = s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.CreatePause(ctx, pause); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				return ErrAlreadyPaused
+			}
+			return mapRepoErr(err, ErrSubscriptionNotFound)
+		}
+		return tx.auditRelated(ctx, model.AuditPause, sub, "pause", nil, pause)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pause, nil
+}
+
+// ResumeSubscription возобновляет подписку с месяца at. По умолчанию — с текущего месяца,
+// а если пауза началась в текущем месяце или позже — со следующего после её начала.
+func (s *Usecase) ResumeSubscription(ctx context.Context, subscriptionID uuid.UUID, at *time.Time) (*model.Pause, error) {
+	sub, err := s.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	pauses, err := s.repo.ListPauses(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var open *model.Pause
+	for i := range pauses {
+		if pauses[i].ResumedAt == nil {
+			open = &pauses[i]
+		}
+	}
+	if open == nil {
+		return nil, ErrNotPaused
+	}
+
+	resumeAt := currentMonth()
+	if at != nil {
+		resumeAt = *at
+	} else if !resumeAt.After(open.PausedFrom) {
+		resumeAt = open.PausedFrom.AddDate(0, 1, 0)
+	}
+	if !resumeAt.After(open.PausedFrom) {
+		return nil, NewValidationError("at", "resume month must be after the pause start")
+	}
+
+	before := *open
+	open.ResumedAt = &resumeAt
+	err = s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.UpdatePause(ctx, open); err != nil {
+			return mapRepoErr(err, ErrNotPaused)
+		}
+		return tx.auditRelated(ctx, model.AuditResume, sub, "pause", &before, open)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return open, nil
+}
+
+func (s *Usecase) ListPauses(ctx context.Context, subscriptionID uuid.UUID) ([]model.Pause, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPauses(ctx, subscriptionID)
+}
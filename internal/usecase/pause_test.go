@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/repository"
+)
+
+func TestPauseAndResume(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	sub := validSubscription()
+	sub.EndDate = ptr(month(2025, 12))
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+
+	// Закрытая пауза февраль–март, дальше шаги выполняются по порядку
+	_, err := s.PauseSubscription(ctx, sub.ID, ptr(month(2025, 2)))
+	require.NoError(t, err)
+	_, err = s.ResumeSubscription(ctx, sub.ID, ptr(month(2025, 4)))
+	require.NoError(t, err)
+
+	steps := []struct {
+		name      string
+		pause     bool
+		at        time.Time
+		wantField string
+		wantErr   error
+	}{
+		{name: "resume when not paused", at: month(2025, 5), wantErr: ErrNotPaused},
+		{name: "pause before start", pause: true, at: month(2024, 12), wantField: "from"},
+		{name: "pause after end", pause: true, at: month(2026, 1), wantField: "from"},
+		{name: "pause overlaps previous", pause: true, at: month(2025, 3), wantField: "from"},
+		{name: "pause in resume month", pause: true, at: month(2025, 4)},
+		{name: "pause twice", pause: true, at: month(2025, 6), wantErr: ErrAlreadyPaused},
+		{name: "resume in pause month", at: month(2025, 4), wantField: "at"},
+		{name: "resume", at: month(2025, 6)},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			var err error
+			if step.pause {
+				_, err = s.PauseSubscription(ctx, sub.ID, &step.at)
+			} else {
+				_, err = s.ResumeSubscription(ctx, sub.ID, &step.at)
+			}
+			switch {
+			case step.wantField != "":
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, step.wantField, validationErr.Field)
+			case step.wantErr != nil:
+				assert.ErrorIs(t, err, step.wantErr)
+				assert.ErrorIs(t, err, ErrConflict)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	pauses, err := s.ListPauses(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, pauses, 2)
+	assert.True(t, pauses[1].PausedFrom.Equal(month(2025, 4)))
+	require.NotNil(t, pauses[1].ResumedAt)
+	assert.True(t, pauses[1].ResumedAt.Equal(month(2025, 6)))
+}
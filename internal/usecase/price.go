@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+)
+
+var ErrPriceChangeNotFound = fmt.Errorf("price change %w", ErrNotFound)
+
+// SchedulePriceChange добавляет изменение цены подписки. Новая цена применяется
+// к списаниям начиная с pc.EffectiveFrom, прошлые списания не пересчитываются.
+func (s *Usecase) SchedulePriceChange(ctx context.Context, pc *model.PriceChange) error {
+	sub, err := s.GetSubscription(ctx, pc.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if pc.Price <= 0 {
+		return NewValidationError("price", "price must be a positive integer")
+	}
+	if !pc.EffectiveFrom.After(sub.StartDate) {
+		return NewValidationError("effective_from", "effective_from must be after start_date, change the subscription price instead")
+	}
+	if until := sub.ActiveUntil(); until != nil && !pc.EffectiveFrom.Before(*until) {
+		return NewValidationError("effective_from", "effective_from must be before or equal to end_date")
+	}
+
+	return s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.CreatePriceChange(ctx, pc); err != nil {
+			return fmt.Errorf("price change for %s: %w", pc.EffectiveFrom.Format("01-2006"), mapRepoErr(err, ErrSubscriptionNotFound))
+		}
+		return tx.auditRelated(ctx, model.AuditPriceChange, sub, "price_change", nil, pc)
+	})
+}
+
+func (s *Usecase) ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]model.PriceChange, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPriceChanges(ctx, subscriptionID)
+}
+
+func (s *Usecase) DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	sub, err := s.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return err
+	}
+	changes, err := s.repo.ListPriceChanges(ctx, subscriptionID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(changes, func(pc model.PriceChange) bool { return pc.ID == id })
+	if i < 0 {
+		return ErrPriceChangeNotFound
+	}
+
+	return s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.DeletePriceChange(ctx, subscriptionID, id); err != nil {
+			return mapRepoErr(err, ErrPriceChangeNotFound)
+		}
+		return tx.auditRelated(ctx, model.AuditPriceChangeDelete, sub, "price_change", &changes[i], nil)
+	})
+}
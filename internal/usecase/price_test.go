@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestSchedulePriceChange(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	sub := validSubscription()
+	sub.EndDate = ptr(month(2025, 12))
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	require.NoError(t, s.SchedulePriceChange(ctx, &model.PriceChange{SubscriptionID: sub.ID, EffectiveFrom: month(2025, 3), Price: 89900}))
+
+	tests := []struct {
+		name           string
+		subscriptionID uuid.UUID
+		effectiveFrom  time.Time
+		price          int
+		wantField      string
+		wantErr        error
+	}{
+		{name: "valid", effectiveFrom: month(2025, 6), price: 99900},
+		{name: "in end month", effectiveFrom: month(2025, 12), price: 99900},
+		{name: "zero price", effectiveFrom: month(2025, 7), price: 0, wantField: "price"},
+		{name: "negative price", effectiveFrom: month(2025, 7), price: -1, wantField: "price"},
+		{name: "at start date", effectiveFrom: month(2025, 1), price: 99900, wantField: "effective_from"},
+		{name: "after end date", effectiveFrom: month(2026, 1), price: 99900, wantField: "effective_from"},
+		{name: "same month twice", effectiveFrom: month(2025, 3), price: 99900, wantErr: ErrConflict},
+		{name: "unknown subscription", subscriptionID: uuid.New(), effectiveFrom: month(2025, 6), price: 99900, wantErr: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := &model.PriceChange{SubscriptionID: sub.ID, EffectiveFrom: tt.effectiveFrom, Price: tt.price}
+			if tt.subscriptionID != uuid.Nil {
+				pc.SubscriptionID = tt.subscriptionID
+			}
+			err := s.SchedulePriceChange(ctx, pc)
+			switch {
+			case tt.wantField != "":
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+				assert.NotEqual(t, uuid.Nil, pc.ID)
+			}
+		})
+	}
+
+	changes, err := s.ListPriceChanges(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.True(t, changes[0].EffectiveFrom.Equal(month(2025, 3)))
+
+	require.NoError(t, s.DeletePriceChange(ctx, sub.ID, changes[0].ID))
+	assert.ErrorIs(t, s.DeletePriceChange(ctx, sub.ID, changes[0].ID), ErrNotFound)
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"subscriptions/internal/model"
+	"subscriptions/internal/statement"
+)
+
+// maxStatementTransactions ограничивает число операций в одной выписке
+const maxStatementTransactions = 50000
+
+// requestDateLayout формат месяцев в SubscriptionReq
+const requestDateLayout = "01-2006"
+
+// AnalyzeStatement ищет в выписке повторяющиеся списания и предлагает по подписке
+// пользователя userID на каждое. Если получатель совпадает с сервисом каталога,
+// кандидат получает его каноническое название и категорию. Кандидат, похожий на уже
+// записанную подписку пользователя, помечается ссылкой на неё. Ничего не сохраняется:
+// выбранные кандидаты создаются обычным созданием подписок.
+func (s *Usecase) AnalyzeStatement(ctx context.Context, userID uuid.UUID, txs []statement.Transaction) (*model.StatementAnalysis, error) {
+	if userID == uuid.Nil {
+		return nil, NewValidationError("user_id", "user_id must not be empty")
+	}
+	if len(txs) == 0 {
+		return nil, NewValidationError("file", "statement has no transactions")
+	}
+	if len(txs) > maxStatementTransactions {
+		return nil, NewValidationError("file", fmt.Sprintf("at most %d transactions are allowed", maxStatementTransactions))
+	}
+
+	existing := make(map[string]uuid.UUID)
+	err := s.repo.ListInBatches(ctx, model.SubscriptionFilter{UserID: &userID}, exportBatchSize, func(batch []model.Subscription) error {
+		for _, sub := range batch {
+			existing[model.NormalizeLabel(sub.ServiceName)] = sub.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.StatementAnalysis{Transactions: len(txs), Candidates: []model.StatementCandidate{}}
+	for _, rec := range statement.Detect(txs) {
+		currency := rec.Currency
+		if currency == "" {
+			currency = defaultCurrency
+		}
+		req := model.SubscriptionReq{
+			ServiceName:   rec.Name,
+			Price:         rec.Amount,
+			Currency:      currency,
+			UserID:        userID,
+			StartDate:     rec.Charges[0].Format(requestDateLayout),
+			BillingPeriod: string(rec.Period),
+		}
+		if rec.Ended {
+			end := rec.Charges[len(rec.Charges)-1].Format(requestDateLayout)
+			req.EndDate = &end
+		}
+
+		svc, err := s.matchMerchant(ctx, rec)
+		if err != nil {
+			return nil, err
+		}
+		if svc != nil {
+			req.ServiceID = &svc.ID
+			req.ServiceName = svc.Name
+			req.Category = svc.DefaultCategory
+		}
+
+		candidate := model.StatementCandidate{
+			Subscription: req,
+			Merchant:     rec.Merchant,
+			Charges:      rec.Charges,
+			Ended:        rec.Ended,
+		}
+		if id, ok := existing[model.NormalizeLabel(req.ServiceName)]; ok {
+			candidate.ExistingSubscriptionID = &id
+		}
+		res.Candidates = append(res.Candidates, candidate)
+	}
+	return res, nil
+}
+
+// matchMerchant ищет сервис каталога для получателя: сначала по записи из выписки,
+// затем по очищенному названию и его началу, например "apple bill" → "apple".
+func (s *Usecase) matchMerchant(ctx context.Context, rec statement.Recurring) (*model.Service, error) {
+	names := []string{rec.Merchant}
+	words := strings.Fields(statement.MerchantKey(rec.Merchant))
+	for n := len(words); n > 0; n-- {
+		names = append(names, strings.Join(words[:n], " "))
+	}
+	for _, name := range names {
+		svc, err := s.MatchService(ctx, name)
+		if err != nil || svc != nil {
+			return svc, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+	"subscriptions/internal/statement"
+)
+
+// monthlyCharges списания amount у payee в первые дни months месяцев 2025 года
+func monthlyCharges(payee string, amount, months int) []statement.Transaction {
+	txs := make([]statement.Transaction, months)
+	for i := range txs {
+		txs[i] = statement.Transaction{Date: month(2025, time.Month(i+1)).AddDate(0, 0, 4), Amount: -amount, Currency: "RUB", Payee: payee}
+	}
+	return txs
+}
+
+func TestAnalyzeStatement(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	require.NoError(t, s.CreateService(ctx, &model.Service{Name: "Netflix", DefaultCategory: "video"}))
+	existing := validSubscription()
+	existing.ServiceName = "Spotify"
+	require.NoError(t, s.CreateSubscription(ctx, existing, false))
+
+	tests := []struct {
+		name         string
+		userID       uuid.UUID
+		txs          []statement.Transaction
+		wantField    string
+		wantName     string
+		wantCategory string
+		wantExisting bool
+	}{
+		{name: "empty user", txs: monthlyCharges("Netflix.com", 79900, 4), wantField: "user_id"},
+		{name: "empty statement", userID: existing.UserID, wantField: "file"},
+		{name: "catalog service", userID: existing.UserID, txs: monthlyCharges("NETFLIX.COM 866-579-7172", 79900, 4), wantName: "Netflix", wantCategory: "video"},
+		{name: "already recorded", userID: existing.UserID, txs: monthlyCharges("SPOTIFY P1A2B3", 29900, 4), wantName: "Spotify", wantExisting: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.AnalyzeStatement(ctx, tt.userID, tt.txs)
+			if tt.wantField != "" {
+				var verr *ValidationError
+				require.ErrorAs(t, err, &verr)
+				assert.Equal(t, tt.wantField, verr.Field)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got.Candidates, 1)
+			c := got.Candidates[0]
+			assert.Equal(t, len(tt.txs), got.Transactions)
+			assert.Equal(t, tt.userID, c.Subscription.UserID)
+			assert.Equal(t, "01-2025", c.Subscription.StartDate)
+			assert.Equal(t, tt.wantCategory, c.Subscription.Category)
+			assert.Equal(t, tt.wantExisting, c.ExistingSubscriptionID != nil)
+			assert.Equal(t, tt.wantName, c.Subscription.ServiceName)
+		})
+	}
+}
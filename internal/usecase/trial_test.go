@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func TestListTrialsEnding(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := today()
+
+	trials := []struct {
+		name     string
+		trialEnd time.Time
+		endDate  *time.Time
+	}{
+		{name: "today", trialEnd: day},
+		{name: "in three days", trialEnd: day.AddDate(0, 0, 3)},
+		{name: "in ten days", trialEnd: day.AddDate(0, 0, 10)},
+		{name: "yesterday", trialEnd: day.AddDate(0, 0, -1)},
+		// Подписка закончится вместе с пробным периодом, платного списания не будет
+		{name: "ends with trial", trialEnd: day.AddDate(0, 0, 2), endDate: ptr(month(day.Year(), day.Month()-1))},
+	}
+	for _, tr := range trials {
+		sub := validSubscription()
+		sub.ServiceName = tr.name
+		sub.StartDate = start
+		sub.TrialEndDate = ptr(tr.trialEnd)
+		sub.EndDate = tr.endDate
+		require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	}
+
+	tests := []struct {
+		name      string
+		days      int
+		want      []string
+		wantField string
+	}{
+		{name: "week", days: 7, want: []string{"today", "in three days"}},
+		{name: "one day", days: 1, want: []string{"today"}},
+		{name: "zero days", days: 0, wantField: "days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subs, err := s.ListTrialsEnding(ctx, model.SubscriptionFilter{}, tt.days)
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, sub := range subs {
+				names = append(names, sub.ServiceName)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
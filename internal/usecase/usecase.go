@@ -1,45 +1,546 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"subscriptions/internal/exchange"
 	"subscriptions/internal/model"
 	"subscriptions/internal/repository"
 )
 
-var ErrSubscriptionNotFound = errors.New("subscription not found")
+const (
+	defaultCurrency = "RUB"
+	// maxLookaheadDays ограничивает горизонт запросов о будущих списаниях
+	maxLookaheadDays = 366
+	// maxForecastMonths ограничивает горизонт прогноза
+	maxForecastMonths = 60
+	maxTags           = 20
+	// exportBatchSize число подписок, читаемых из хранилища за один запрос при выгрузке
+	exportBatchSize = 500
+)
 
 type Usecase struct {
-	repo repository.Repository
+	repo           repository.Repository
+	rates          exchange.RateProvider
+	notifier       BudgetNotifier
+	calendarSecret []byte
+}
+
+// New создаёт usecase. rates может быть nil — тогда перевод сумм между валютами недоступен,
+// notifier может быть nil — тогда превышение бюджетов не проверяется, пустой calendarSecret
+// отключает ленту списаний в формате iCalendar.
+func New(repo repository.Repository, rates exchange.RateProvider, notifier BudgetNotifier, calendarSecret []byte) *Usecase {
+	return &Usecase{repo: repo, rates: rates, notifier: notifier, calendarSecret: calendarSecret}
+}
+
+// inTransaction выполняет fn с копией usecase, работающей в транзакции хранилища.
+// События о бюджетах откладываются до фиксации, чтобы откат не оставлял ложных оповещений.
+func (s *Usecase) inTransaction(ctx context.Context, fn func(tx *Usecase) error) error {
+	var pending pendingAlerts
+	err := s.repo.Transaction(ctx, func(repo repository.Repository) error {
+		tx := *s
+		tx.repo = repo
+		if s.notifier != nil {
+			tx.notifier = &pending
+		}
+		return fn(&tx)
+	})
+	if err != nil {
+		return err
+	}
+	for _, alert := range pending {
+		s.notifier.BudgetExceeded(ctx, alert)
+	}
+	return nil
+}
+
+// pendingAlerts копит события о превышении бюджетов до фиксации транзакции.
+type pendingAlerts []model.BudgetAlert
+
+func (p *pendingAlerts) BudgetExceeded(_ context.Context, alert model.BudgetAlert) {
+	*p = append(*p, alert)
+}
+
+// CreateSubscription создаёт подписку. Если allowDuplicate не задан, а у пользователя уже есть
+// похожая подписка с общим периодом действия, возвращается *DuplicateError.
+func (s *Usecase) CreateSubscription(ctx context.Context, sub *model.Subscription, allowDuplicate bool) error {
+	if err := validateSubscription(sub); err != nil {
+		return err
+	}
+	if err := s.resolveService(ctx, sub); err != nil {
+		return err
+	}
+	check := s.prepareBudgetCheck(ctx, sub)
+	// Изменение и запись о нём в журнале сохраняются вместе или не сохраняются вовсе
+	err := s.inTransaction(ctx, func(tx *Usecase) error {
+		if !allowDuplicate {
+			// Блокировка не даёт двум одновременным запросам создать одинаковые подписки,
+			// пока каждый не видит чужую
+			if err := tx.repo.LockUser(ctx, sub.UserID); err != nil {
+				return err
+			}
+			if err := tx.checkDuplicate(ctx, sub); err != nil {
+				return err
+			}
+		}
+		if err := tx.repo.Create(ctx, sub); err != nil {
+			return mapRepoErr(err, ErrSubscriptionNotFound)
+		}
+		return tx.audit(ctx, model.AuditCreate, nil, sub)
+	})
+	if err != nil {
+		return err
+	}
+	s.notifyBudgets(ctx, sub, check)
+	return nil
+}
+
+func (s *Usecase) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapRepoErr(err, ErrSubscriptionNotFound)
+	}
+	return sub, nil
+}
+
+// UpdateSubscription заменяет поля подписки. Данные об отмене меняются только через CancelSubscription.
+// Если sub.Version не ноль, подписка обновляется только в этой версии, иначе — ErrVersionMismatch.
+func (s *Usecase) UpdateSubscription(ctx context.Context, sub *model.Subscription) error {
+	if err := validateSubscription(sub); err != nil {
+		return err
+	}
+	before, err := s.GetSubscription(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+	if sub.Version != 0 && sub.Version != before.Version {
+		return ErrVersionMismatch
+	}
+	// Хранилище повторно сверяет версию, поэтому изменение между чтением и записью тоже не потеряется
+	sub.Version = before.Version
+	sub.CanceledAt, sub.CancelReason = before.CanceledAt, before.CancelReason
+	if err := s.resolveService(ctx, sub); err != nil {
+		return err
+	}
+	check := s.prepareBudgetCheck(ctx, sub)
+	err = s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.Update(ctx, sub); err != nil {
+			return mapRepoErr(err, ErrSubscriptionNotFound)
+		}
+		return tx.audit(ctx, model.AuditUpdate, before, sub)
+	})
+	if err != nil {
+		return err
+	}
+	s.notifyBudgets(ctx, sub, check)
+	return nil
+}
+
+// DeleteSubscription мягко удаляет подписку: она пропадает из списков и прогнозов,
+// но её прошлые списания остаются в суммах до окончательной очистки.
+func (s *Usecase) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	before, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.Delete(ctx, id); err != nil {
+			return mapRepoErr(err, ErrSubscriptionNotFound)
+		}
+		after := *before
+		after.MarkDeleted(time.Now().UTC())
+		return tx.audit(ctx, model.AuditDelete, before, &after)
+	})
+}
+
+// RestoreSubscription отменяет мягкое удаление подписки.
+func (s *Usecase) RestoreSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	before, err := s.repo.GetDeleted(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Отличаем неудалённую подписку от отсутствующей
+		if _, getErr := s.GetSubscription(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrNotDeleted
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var after *model.Subscription
+	err = s.inTransaction(ctx, func(tx *Usecase) error {
+		if err := tx.repo.Restore(ctx, id); err != nil {
+			return mapRepoErr(err, ErrSubscriptionNotFound)
+		}
+		var err error
+		if after, err = tx.GetSubscription(ctx, id); err != nil {
+			return err
+		}
+		return tx.audit(ctx, model.AuditRestore, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// PurgeDeleted окончательно удаляет подписки, удалённые мягко дольше retention назад,
+// и возвращает их число.
+func (s *Usecase) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	var purged []model.Subscription
+	err := s.inTransaction(ctx, func(tx *Usecase) error {
+		var err error
+		if purged, err = tx.repo.PurgeDeleted(ctx, time.Now().Add(-retention)); err != nil {
+			return err
+		}
+		for i := range purged {
+			if err := tx.audit(ctx, model.AuditPurge, &purged[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(purged)), nil
+}
+
+func (s *Usecase) ListSubscriptions(ctx context.Context, filter model.SubscriptionFilter, limit, offset int) (*model.SubscriptionList, error) {
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, filter, limit, offset)
+}
+
+// ExportSubscriptions передаёт в fn все подписки под фильтром порциями,
+// не загружая их в память целиком.
+func (s *Usecase) ExportSubscriptions(ctx context.Context, filter model.SubscriptionFilter, fn func(batch []model.Subscription) error) error {
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return s.repo.ListInBatches(ctx, filter, exportBatchSize, fn)
 }
 
-func New(repo repository.Repository) *Usecase {
-	return &Usecase{repo: repo}
+// CalculateTotal Подсчёт суммарной стоимости подписок за период.
+// Если currency задана, итог переводится в неё по текущему курсу.
+func (s *Usecase) CalculateTotal(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time, currency string) (*model.Total, error) {
+	if from.After(to) {
+		return nil, NewValidationError("from", "from must be before or equal to to")
+	}
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	// Удалённые подписки остаются в исторических суммах до момента удаления
+	filter.IncludeDeleted = true
+	byCurrency, err := s.repo.CalculateTotal(ctx, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, byCurrency, currency)
 }
 
-func (s *Usecase) CreateSubscription(sub *model.Subscription) error {
-	return s.repo.Create(sub)
+// CalculateTotalByCategory считает ту же сумму, что и CalculateTotal, отдельно по каждой категории.
+// Если currency задана, итог каждой категории переводится в неё.
+func (s *Usecase) CalculateTotalByCategory(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time, currency string) ([]model.CategoryTotal, error) {
+	if from.After(to) {
+		return nil, NewValidationError("from", "from must be before or equal to to")
+	}
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	filter.IncludeDeleted = true
+	amounts, err := s.repo.CalculateTotalByCategory(ctx, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Суммы упорядочены по категории, поэтому валюты одной категории идут подряд
+	totals := []model.CategoryTotal{}
+	for i := 0; i < len(amounts); {
+		category := amounts[i].Category
+		byCurrency := make(map[string]int)
+		for ; i < len(amounts) && amounts[i].Category == category; i++ {
+			byCurrency[amounts[i].Currency] += amounts[i].Amount
+		}
+		total, err := s.summarize(ctx, byCurrency, currency)
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, model.CategoryTotal{Category: category, Total: *total})
+	}
+	return totals, nil
+}
+
+// CalculateBreakdown разбивает сумму CalculateTotal по месяцам и сервисам.
+// Если currency задана, суммы переводятся в неё и объединяются по месяцу и сервису.
+func (s *Usecase) CalculateBreakdown(ctx context.Context, filter model.SubscriptionFilter, from, to time.Time, currency string) ([]model.BreakdownItem, error) {
+	if from.After(to) {
+		return nil, NewValidationError("from", "from must be before or equal to to")
+	}
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	filter.IncludeDeleted = true
+	items, err := s.repo.CalculateBreakdown(ctx, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		return items, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if !exchange.ValidCode(currency) {
+		return nil, NewValidationError("currency", "currency must be an ISO 4217 code")
+	}
+
+	// Элементы упорядочены по месяцу и сервису, поэтому одинаковые пары идут подряд
+	merged := make([]model.BreakdownItem, 0, len(items))
+	for _, item := range items {
+		amount, err := s.convert(ctx, item.Amount, item.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(merged); n > 0 && merged[n-1].Month.Equal(item.Month) && merged[n-1].ServiceName == item.ServiceName {
+			merged[n-1].Amount += amount
+			continue
+		}
+		item.Amount, item.Currency = amount, currency
+		merged = append(merged, item)
+	}
+	return merged, nil
+}
+
+// Forecast прогнозирует списания на months месяцев вперёд начиная с текущего:
+// по активным подпискам с учётом циклов, дат окончания, пауз и запланированных
+// изменений цены. Текущий месяц учитывается начиная с сегодняшнего дня.
+func (s *Usecase) Forecast(ctx context.Context, filter model.SubscriptionFilter, months int, currency string) ([]model.ForecastMonth, error) {
+	if months <= 0 || months > maxForecastMonths {
+		return nil, NewValidationError("months", fmt.Sprintf("months must be between 1 and %d", maxForecastMonths))
+	}
+
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	start := currentMonth()
+	amounts, err := s.repo.ForecastByMonth(ctx, filter, today(), start.AddDate(0, months, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[time.Time]map[string]int, months)
+	for _, a := range amounts {
+		month := a.Month.UTC()
+		if byMonth[month] == nil {
+			byMonth[month] = make(map[string]int)
+		}
+		byMonth[month][a.Currency] += a.Amount
+	}
+
+	// Месяцы без списаний тоже попадают в прогноз — с нулевой суммой
+	forecast := make([]model.ForecastMonth, 0, months)
+	for i := 0; i < months; i++ {
+		month := start.AddDate(0, i, 0)
+		byCurrency := byMonth[month]
+		if byCurrency == nil {
+			byCurrency = map[string]int{}
+		}
+		total, err := s.summarize(ctx, byCurrency, currency)
+		if err != nil {
+			return nil, err
+		}
+		forecast = append(forecast, model.ForecastMonth{Month: month, Total: *total})
+	}
+	return forecast, nil
+}
+
+// UpcomingCharges возвращает списания, ожидаемые в ближайшие days дней начиная с сегодняшнего.
+func (s *Usecase) UpcomingCharges(ctx context.Context, filter model.SubscriptionFilter, days int) ([]model.Charge, error) {
+	if days <= 0 || days > maxLookaheadDays {
+		return nil, NewValidationError("days", fmt.Sprintf("days must be between 1 and %d", maxLookaheadDays))
+	}
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	from := today()
+	return s.repo.ListCharges(ctx, filter, from, from.AddDate(0, 0, days))
+}
+
+// ListTrialsEnding возвращает подписки, пробный период которых заканчивается
+// в ближайшие days дней, — чтобы пользователь успел отменить их до первого списания.
+func (s *Usecase) ListTrialsEnding(ctx context.Context, filter model.SubscriptionFilter, days int) ([]model.Subscription, error) {
+	if days <= 0 || days > maxLookaheadDays {
+		return nil, NewValidationError("days", fmt.Sprintf("days must be between 1 and %d", maxLookaheadDays))
+	}
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	// Пробный период заканчивается в полночь первого числа: сегодняшнее окончание тоже попадает в список
+	from := today()
+	return s.repo.ListTrialsEnding(ctx, filter, from, from.AddDate(0, 0, days))
+}
+
+// summarize собирает итог по валютам и при необходимости переводит его в currency.
+func (s *Usecase) summarize(ctx context.Context, byCurrency map[string]int, currency string) (*model.Total, error) {
+	res := &model.Total{ByCurrency: byCurrency}
+
+	if currency == "" {
+		// Без целевой валюты общий итог имеет смысл, только если валюта одна
+		switch len(byCurrency) {
+		case 0:
+			zero := 0
+			res.Total = &zero
+		case 1:
+			for code, amount := range byCurrency {
+				res.Total, res.Currency = &amount, code
+			}
+		}
+		return res, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if !exchange.ValidCode(currency) {
+		return nil, NewValidationError("currency", "currency must be an ISO 4217 code")
+	}
+
+	total := 0
+	for code, amount := range byCurrency {
+		converted, err := s.convert(ctx, amount, code, currency)
+		if err != nil {
+			return nil, err
+		}
+		total += converted
+	}
+	res.Total, res.Currency = &total, currency
+	return res, nil
+}
+
+func (s *Usecase) convert(ctx context.Context, amount int, from, to string) (int, error) {
+	if from == to {
+		return amount, nil
+	}
+	if s.rates == nil {
+		return 0, NewValidationError("currency", "currency conversion is not configured")
+	}
+	converted, err := exchange.Convert(ctx, s.rates, amount, from, to)
+	if errors.Is(err, exchange.ErrUnknownCurrency) {
+		return 0, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	return converted, err
+}
+
+// validateSubscription проверяет подписку и подставляет значения по умолчанию
+// для незаполненного цикла списаний.
+func validateSubscription(sub *model.Subscription) error {
+	if sub.BillingPeriod == "" {
+		sub.BillingPeriod = model.BillingMonth
+	}
+	if sub.BillingInterval == 0 {
+		sub.BillingInterval = 1
+	}
+	sub.Currency = strings.ToUpper(strings.TrimSpace(sub.Currency))
+	if sub.Currency == "" {
+		sub.Currency = defaultCurrency
+	}
+	sub.Category = model.NormalizeLabel(sub.Category)
+	sub.Tags = normalizeLabels(sub.Tags)
+
+	if strings.TrimSpace(sub.ServiceName) == "" {
+		return NewValidationError("service_name", "service_name must not be empty")
+	}
+	if sub.Price <= 0 {
+		return NewValidationError("price", "price must be a positive integer")
+	}
+	if sub.UserID == uuid.Nil {
+		return NewValidationError("user_id", "user_id must not be empty")
+	}
+	if !validYear(sub.StartDate) {
+		return NewValidationError("start_date", yearRangeMessage("start_date"))
+	}
+	if sub.EndDate != nil && !validYear(*sub.EndDate) {
+		return NewValidationError("end_date", yearRangeMessage("end_date"))
+	}
+	if sub.TrialEndDate != nil && !validYear(*sub.TrialEndDate) {
+		return NewValidationError("trial_end_date", yearRangeMessage("trial_end_date"))
+	}
+	if sub.EndDate != nil && sub.StartDate.After(*sub.EndDate) {
+		return NewValidationError("end_date", "start_date must be before or equal to end_date")
+	}
+	if sub.TrialEndDate != nil && sub.TrialEndDate.Before(sub.StartDate) {
+		return NewValidationError("trial_end_date", "trial_end_date must be after or equal to start_date")
+	}
+	if !sub.BillingPeriod.Valid() {
+		return NewValidationError("billing_period", "billing_period must be one of day, week, month, quarter, year")
+	}
+	if sub.BillingInterval < 1 {
+		return NewValidationError("billing_interval", "billing_interval must be a positive integer")
+	}
+	if limit := sub.BillingPeriod.MaxInterval(); sub.BillingInterval > limit {
+		return NewValidationError("billing_interval", fmt.Sprintf("billing_interval must be at most %d for %s", limit, sub.BillingPeriod))
+	}
+	if !exchange.ValidCode(sub.Currency) {
+		return NewValidationError("currency", "currency must be an ISO 4217 code")
+	}
+	if len(sub.Tags) > maxTags {
+		return NewValidationError("tags", fmt.Sprintf("at most %d tags are allowed", maxTags))
+	}
+	return nil
 }
 
-func (s *Usecase) GetSubscription(id uuid.UUID) (*model.Subscription, error) {
-	return s.repo.GetByID(id)
+// validYear проверяет, что дата подписки попадает в годы model.MinYear–model.MaxYear.
+func validYear(t time.Time) bool {
+	return t.Year() >= model.MinYear && t.Year() <= model.MaxYear
 }
 
-func (s *Usecase) UpdateSubscription(sub *model.Subscription) error {
-	return s.repo.Update(sub)
+func yearRangeMessage(field string) string {
+	return fmt.Sprintf("%s year must be between %d and %d", field, model.MinYear, model.MaxYear)
 }
 
-func (s *Usecase) DeleteSubscription(id uuid.UUID) error {
-	return s.repo.Delete(id)
+// normalizeLabels нормализует метки или псевдонимы, отбрасывает пустые и повторы
+// и сортирует по алфавиту.
+func normalizeLabels(labels []string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(labels))
+	out := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = model.NormalizeLabel(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		out = append(out, label)
+	}
+	sort.Strings(out)
+	return out
 }
 
-func (s *Usecase) ListSubscriptions(userID *uuid.UUID, serviceName *string, limit, offset int) (*model.SubscriptionList, error) {
-	return s.repo.List(userID, serviceName, limit, offset)
+// currentMonth возвращает первое число текущего месяца в UTC — в том же виде,
+// в каком хранятся даты в формате MM-YYYY.
+func currentMonth() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
 }
 
-// CalculateTotal Подсчёт суммарной стоимости подписок за период
-func (s *Usecase) CalculateTotal(userID *uuid.UUID, serviceName *string, from, to time.Time) (int, error) {
-	return s.repo.CalculateTotal(userID, serviceName, from, to)
+// today возвращает начало текущего дня в UTC.
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 }
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"subscriptions/internal/exchange"
+	"subscriptions/internal/model"
+	"subscriptions/internal/repository"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// validSubscription возвращает подписку, проходящую проверки, для изменения в тестах.
+func validSubscription() *model.Subscription {
+	return &model.Subscription{
+		ServiceName: "Netflix",
+		Price:       79900,
+		UserID:      uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		StartDate:   month(2025, 1),
+	}
+}
+
+func TestValidateSubscription(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(sub *model.Subscription)
+		wantField string
+	}{
+		{name: "valid", modify: func(*model.Subscription) {}},
+		{name: "blank service name", modify: func(sub *model.Subscription) { sub.ServiceName = "  " }, wantField: "service_name"},
+		{name: "zero price", modify: func(sub *model.Subscription) { sub.Price = 0 }, wantField: "price"},
+		{name: "negative price", modify: func(sub *model.Subscription) { sub.Price = -1 }, wantField: "price"},
+		{name: "no user", modify: func(sub *model.Subscription) { sub.UserID = uuid.Nil }, wantField: "user_id"},
+		{name: "end before start", modify: func(sub *model.Subscription) { sub.EndDate = ptr(month(2024, 12)) }, wantField: "end_date"},
+		{name: "end in start month", modify: func(sub *model.Subscription) { sub.EndDate = ptr(month(2025, 1)) }},
+		{name: "unknown period", modify: func(sub *model.Subscription) { sub.BillingPeriod = "decade" }, wantField: "billing_period"},
+		{name: "negative interval", modify: func(sub *model.Subscription) { sub.BillingInterval = -1 }, wantField: "billing_interval"},
+		{name: "ten years of months", modify: func(sub *model.Subscription) { sub.BillingInterval = 120 }},
+		{name: "interval over ten years", modify: func(sub *model.Subscription) { sub.BillingInterval = 121 }, wantField: "billing_interval"},
+		{
+			name: "huge weekly interval",
+			modify: func(sub *model.Subscription) {
+				sub.BillingPeriod = model.BillingWeek
+				sub.BillingInterval = 1 << 40
+			},
+			wantField: "billing_interval",
+		},
+		{name: "start before 1970", modify: func(sub *model.Subscription) { sub.StartDate = month(1969, 12) }, wantField: "start_date"},
+		{name: "start in year one", modify: func(sub *model.Subscription) { sub.StartDate = time.Time{} }, wantField: "start_date"},
+		{name: "end after 2100", modify: func(sub *model.Subscription) { sub.EndDate = ptr(month(2101, 1)) }, wantField: "end_date"},
+		{name: "end in 2100", modify: func(sub *model.Subscription) { sub.EndDate = ptr(month(2100, 12)) }},
+		{name: "trial ends on start", modify: func(sub *model.Subscription) { sub.TrialEndDate = ptr(month(2025, 1)) }},
+		{name: "trial ends before start", modify: func(sub *model.Subscription) { sub.TrialEndDate = ptr(month(2024, 12)) }, wantField: "trial_end_date"},
+		{name: "trial ends after 2100", modify: func(sub *model.Subscription) { sub.TrialEndDate = ptr(month(2101, 1)) }, wantField: "trial_end_date"},
+		{name: "currency is normalized", modify: func(sub *model.Subscription) { sub.Currency = " usd " }},
+		{name: "invalid currency", modify: func(sub *model.Subscription) { sub.Currency = "рубль" }, wantField: "currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := validSubscription()
+			tt.modify(sub)
+			err := validateSubscription(sub)
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+			assert.ErrorIs(t, err, ErrValidation)
+		})
+	}
+}
+
+func TestRepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+
+	_, err := s.GetSubscription(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, s.DeleteSubscription(ctx, uuid.New()), ErrNotFound)
+
+	sub := validSubscription()
+	sub.ID = uuid.New()
+	assert.ErrorIs(t, s.UpdateSubscription(ctx, sub), ErrNotFound)
+
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+	dup := validSubscription()
+	dup.ID = sub.ID
+	assert.ErrorIs(t, s.CreateSubscription(ctx, dup, false), ErrConflict)
+
+	_, err = s.CalculateTotal(ctx, model.SubscriptionFilter{}, month(2025, 2), month(2025, 1), "")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+// rubRates курсы для тестов: стоимость одной единицы валюты в рублях.
+type rubRates map[string]float64
+
+func (r rubRates) Rate(_ context.Context, from, to string) (float64, error) {
+	fromRate, ok := r[from]
+	if !ok {
+		return 0, exchange.ErrUnknownCurrency
+	}
+	toRate, ok := r[to]
+	if !ok {
+		return 0, exchange.ErrUnknownCurrency
+	}
+	return fromRate / toRate, nil
+}
+
+func TestSummarize(t *testing.T) {
+	rates := rubRates{"RUB": 1, "USD": 80}
+
+	tests := []struct {
+		name         string
+		rates        exchange.RateProvider
+		byCurrency   map[string]int
+		currency     string
+		wantTotal    *int
+		wantCurrency string
+		wantField    string
+		wantErr      error
+	}{
+		{
+			name:       "no charges",
+			byCurrency: map[string]int{},
+			wantTotal:  ptr(0),
+		},
+		{
+			name:         "single currency",
+			byCurrency:   map[string]int{"USD": 999},
+			wantTotal:    ptr(999),
+			wantCurrency: "USD",
+		},
+		{
+			// Без целевой валюты суммы в разных валютах не складываются
+			name:       "several currencies",
+			byCurrency: map[string]int{"RUB": 10000, "USD": 999},
+		},
+		{
+			name:         "converted",
+			rates:        rates,
+			byCurrency:   map[string]int{"RUB": 10000, "USD": 999},
+			currency:     "rub",
+			wantTotal:    ptr(10000 + 79920),
+			wantCurrency: "RUB",
+		},
+		{
+			name:       "invalid target currency",
+			rates:      rates,
+			byCurrency: map[string]int{"RUB": 10000},
+			currency:   "рубль",
+			wantField:  "currency",
+		},
+		{
+			name:       "conversion not configured",
+			byCurrency: map[string]int{"USD": 999},
+			currency:   "RUB",
+			wantField:  "currency",
+		},
+		{
+			name:         "same currency without rates",
+			byCurrency:   map[string]int{"RUB": 10000},
+			currency:     "RUB",
+			wantTotal:    ptr(10000),
+			wantCurrency: "RUB",
+		},
+		{
+			name:       "unknown rate",
+			rates:      rates,
+			byCurrency: map[string]int{"EUR": 999},
+			currency:   "RUB",
+			wantErr:    exchange.ErrUnknownCurrency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(repository.NewMemoryRepository(), tt.rates, nil, nil)
+			total, err := s.summarize(context.Background(), tt.byCurrency, tt.currency)
+			switch {
+			case tt.wantField != "":
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrValidation)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total.Total)
+			assert.Equal(t, tt.wantCurrency, total.Currency)
+			assert.Equal(t, tt.byCurrency, total.ByCurrency)
+		})
+	}
+}
+
+func TestUpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	s := New(repository.NewMemoryRepository(), nil, nil, nil)
+	sub := validSubscription()
+	require.NoError(t, s.CreateSubscription(ctx, sub, false))
+
+	tests := []struct {
+		name        string
+		version     int64
+		wantErr     error
+		wantVersion int64
+	}{
+		{name: "current", version: 1, wantVersion: 2},
+		{name: "stale", version: 1, wantErr: ErrPreconditionFailed},
+		{name: "unchecked", version: 0, wantVersion: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := validSubscription()
+			update.ID = sub.ID
+			update.Version = tt.version
+			err := s.UpdateSubscription(ctx, update)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, ErrVersionMismatch)
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, update.Version)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN billing_period   TEXT    NOT NULL DEFAULT 'month',
+    ADD COLUMN billing_interval INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_billing_period_check
+        CHECK (billing_period IN ('day', 'week', 'month', 'quarter', 'year')),
+    ADD CONSTRAINT subscriptions_billing_interval_check
+        CHECK (billing_interval > 0);
+
+-- +goose Down
+ALTER TABLE subscriptions
+    DROP COLUMN billing_interval,
+    DROP COLUMN billing_period;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Цены переводятся в минимальные единицы валюты: все существующие записи были в рублях.
+ALTER TABLE subscriptions
+    ALTER COLUMN price TYPE BIGINT,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+UPDATE subscriptions
+SET price = price * 100;
+
+-- +goose Down
+UPDATE subscriptions
+SET price = price / 100;
+
+ALTER TABLE subscriptions
+    DROP COLUMN currency,
+    ALTER COLUMN price TYPE INTEGER;
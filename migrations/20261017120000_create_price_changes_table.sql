@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE price_changes
+(
+    id              UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    subscription_id UUID      NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    effective_from  DATE      NOT NULL,
+    price           BIGINT    NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_price_changes_subscription_date ON price_changes (subscription_id, effective_from);
+
+-- +goose Down
+DROP TABLE IF EXISTS price_changes;
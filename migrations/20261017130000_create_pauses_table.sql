@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE pauses
+(
+    id              UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    subscription_id UUID      NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    paused_from     DATE      NOT NULL,
+    resumed_at      DATE,
+    created_at      TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK (resumed_at IS NULL OR resumed_at > paused_from)
+);
+
+CREATE INDEX idx_pauses_subscription_id ON pauses (subscription_id);
+-- Не больше одной открытой паузы на подписку
+CREATE UNIQUE INDEX idx_pauses_open ON pauses (subscription_id) WHERE resumed_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS pauses;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN trial_end_date DATE;
+
+CREATE INDEX idx_subscriptions_trial_end_date ON subscriptions (trial_end_date) WHERE trial_end_date IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE subscriptions
+    DROP COLUMN trial_end_date;
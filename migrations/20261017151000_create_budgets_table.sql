@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE budgets
+(
+    id         UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    user_id    UUID      NOT NULL,
+    amount     BIGINT    NOT NULL CHECK (amount > 0),
+    currency   CHAR(3)   NOT NULL DEFAULT 'RUB',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Не больше одного бюджета у пользователя
+CREATE UNIQUE INDEX idx_budgets_user ON budgets (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS budgets;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+-- Кроме общего бюджета пользователь может завести по одному бюджету на категорию
+ALTER TABLE budgets
+    ADD COLUMN category TEXT NOT NULL DEFAULT '';
+DROP INDEX IF EXISTS idx_budgets_user;
+CREATE UNIQUE INDEX idx_budgets_user_category ON budgets (user_id, category);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_budgets_user_category;
+DELETE FROM budgets WHERE category <> '';
+CREATE UNIQUE INDEX idx_budgets_user ON budgets (user_id);
+ALTER TABLE budgets
+    DROP COLUMN category;
+
+ALTER TABLE subscriptions
+    DROP COLUMN category;
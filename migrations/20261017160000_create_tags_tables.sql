@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE tags
+(
+    id   UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE subscription_tags
+(
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    tag_id          UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (subscription_id, tag_id)
+);
+
+CREATE INDEX idx_subscription_tags_tag_id ON subscription_tags (tag_id);
+CREATE INDEX idx_subscriptions_category ON subscriptions (user_id, category);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_subscriptions_category;
+DROP TABLE IF EXISTS subscription_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE services
+(
+    id               UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    name             TEXT      NOT NULL,
+    default_category TEXT      NOT NULL DEFAULT '',
+    url              TEXT      NOT NULL DEFAULT '',
+    logo_url         TEXT      NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Нормализованные псевдонимы (нижний регистр, одиночные пробелы), включая само название
+CREATE TABLE service_aliases
+(
+    alias      TEXT PRIMARY KEY,
+    service_id UUID NOT NULL REFERENCES services (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_service_aliases_service_id ON service_aliases (service_id);
+
+ALTER TABLE subscriptions
+    ADD COLUMN service_id UUID REFERENCES services (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_subscriptions_service_id ON subscriptions (service_id);
+
+-- +goose Down
+ALTER TABLE subscriptions
+    DROP COLUMN service_id;
+DROP TABLE IF EXISTS service_aliases;
+DROP TABLE IF EXISTS services;
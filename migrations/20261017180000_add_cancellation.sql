@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN canceled_at   TIMESTAMP,
+    ADD COLUMN cancel_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_subscriptions_canceled_at ON subscriptions (canceled_at) WHERE canceled_at IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE subscriptions
+    DROP COLUMN cancel_reason,
+    DROP COLUMN canceled_at;
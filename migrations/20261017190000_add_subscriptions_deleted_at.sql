@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_subscriptions_deleted_at ON subscriptions (deleted_at);
+
+-- +goose Down
+DELETE FROM subscriptions WHERE deleted_at IS NOT NULL;
+ALTER TABLE subscriptions
+    DROP COLUMN deleted_at;
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE audit_log
+(
+    id              UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    -- Без внешнего ключа: записи переживают окончательное удаление подписки
+    subscription_id UUID      NOT NULL,
+    user_id         UUID      NOT NULL,
+    actor           TEXT      NOT NULL,
+    operation       TEXT      NOT NULL,
+    changes         JSONB     NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_subscription_id ON audit_log (subscription_id);
+CREATE INDEX idx_audit_log_user_id ON audit_log (user_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+-- Журнал только дополняется
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION audit_log_append_only();
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE subscriptions
+    DROP COLUMN version;